@@ -0,0 +1,123 @@
+package main
+
+import (
+  "fmt"
+  "image"
+  "path/filepath"
+  "strings"
+)
+
+const (
+  adaptiveIconDp = 108
+  adaptiveVisibleDp = 72
+  adaptiveIconFolder = "mipmap-anydpi-v26"
+)
+
+const adaptiveIconXML = `<?xml version="1.0" encoding="utf-8"?>
+<adaptive-icon xmlns:android="http://schemas.android.com/apk/res/android">
+    <background android:drawable="%s"/>
+    <foreground android:drawable="%s"/>
+%s</adaptive-icon>
+`
+
+const monochromeXML = `    <monochrome android:drawable="%s"/>
+`
+
+const backgroundColorXML = `<?xml version="1.0" encoding="utf-8"?>
+<resources>
+    <color name="%s">%s</color>
+</resources>
+`
+
+type AdaptiveIcon struct {
+  Foreground image.Image
+  Background image.Image
+  BackgroundColor string
+  Monochrome image.Image
+}
+
+// loadAdaptiveIcon reads the foreground layer and a background that is either
+// an image or a #RRGGBB color.
+func loadAdaptiveIcon(foreground string, background string) (icon AdaptiveIcon, err error) {
+  icon.Foreground, _, err = decodeImageFile(foreground)
+  if err != nil { return }
+
+  if background == "" {
+    background = "#FFFFFF"
+  }
+  if strings.HasPrefix(background, "#") {
+    c, colorErr := parseHexColor(background)
+    if colorErr != nil {
+      return icon, colorErr
+    }
+    icon.BackgroundColor = strings.ToUpper(background)
+    icon.Background = solidImage(1, 1, c)
+    return
+  }
+  icon.Background, _, err = decodeImageFile(background)
+  return
+}
+
+// loadMonochrome sets the themed icon layer, either from an image or, for
+// "auto", by thresholding the foreground's alpha.
+func (icon *AdaptiveIcon) loadMonochrome(source string, cutoff float64) (err error) {
+  if source == "auto" {
+    icon.Monochrome = threshold(icon.Foreground, cutoff)
+    return nil
+  }
+  icon.Monochrome, _, err = decodeImageFile(source)
+  return
+}
+
+// legacyMaster flattens the layers and crops them to the part of the icon a
+// launcher mask shows, for devices without adaptive icon support.
+func (icon *AdaptiveIcon) legacyMaster() image.Image {
+  size, _ := getDimens(&icon.Foreground)
+  flattened := compose(size, icon.Background, icon.Foreground)
+  visible := size * adaptiveVisibleDp / adaptiveIconDp
+  return cropCenter(flattened, visible, visible)
+}
+
+func writeAdaptiveIcon(resFolder string, name string, icon *AdaptiveIcon) error {
+  if err := writeMipmaps(resFolder, name + "_foreground.png", icon.Foreground, adaptiveIconDp); err != nil {
+    return err
+  }
+
+  backgroundName := name + "_background"
+  if icon.BackgroundColor != "" {
+    path := filepath.Join(resFolder, "values", backgroundName + ".xml")
+    if !skipExisting(path) {
+      if err := writeFile(path, []byte(fmt.Sprintf(backgroundColorXML, backgroundName, icon.BackgroundColor))); err != nil {
+        return err
+      }
+    }
+  } else if err := writeMipmaps(resFolder, backgroundName + ".png", icon.Background, adaptiveIconDp); err != nil {
+    return err
+  }
+
+  if icon.Monochrome != nil {
+    if err := writeMipmaps(resFolder, name + "_monochrome.png", icon.Monochrome, adaptiveIconDp); err != nil {
+      return err
+    }
+  }
+  return writeAdaptiveIconXML(resFolder, name, name, icon)
+}
+
+// writeAdaptiveIconXML writes the anydpi-v26 wrapper called name, pointing at
+// the layers generated for layerName.
+func writeAdaptiveIconXML(resFolder string, name string, layerName string, icon *AdaptiveIcon) error {
+  backgroundRef := "@mipmap/" + layerName + "_background"
+  if icon.BackgroundColor != "" {
+    backgroundRef = "@color/" + layerName + "_background"
+  }
+  monochrome := ""
+  if icon.Monochrome != nil {
+    monochrome = fmt.Sprintf(monochromeXML, "@mipmap/" + layerName + "_monochrome")
+  }
+
+  path := filepath.Join(resFolder, adaptiveIconFolder, name + ".xml")
+  if skipExisting(path) {
+    return nil
+  }
+  return writeFile(path, []byte(fmt.Sprintf(adaptiveIconXML, backgroundRef, "@mipmap/" + layerName + "_foreground", monochrome)))
+}
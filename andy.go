@@ -4,16 +4,16 @@ import (
   "github.com/nfnt/resize"
   "strings"
   "image"
-  "image/png"
   "log"
   "os"
-  "strconv"
-  "regexp"
   "path/filepath"
   "github.com/spf13/cobra"
   "github.com/fatih/color"
   "fmt"
   "errors"
+  "math"
+  "runtime"
+  "sort"
 )
 
 type dpi float64
@@ -22,6 +22,15 @@ type DrawableInfo struct {
   ResFolder string
   Density dpi
   Filename string
+  Qualifier string
+  Format string
+  // NinePatch keeps the 1px border intact when resizing
+  NinePatch bool
+  // Skip lists densities not to generate
+  Skip map[dpi]bool
+  // Asset is the argument being generated from, what's written for it is
+  // counted under
+  Asset string
 }
 
 const (
@@ -71,8 +80,51 @@ var (
   }
 
   green = color.New(color.FgGreen).SprintfFunc()
+  yellow = color.New(color.FgYellow).SprintfFunc()
+  red = color.New(color.FgRed).SprintfFunc()
+
+  resDirs []string
 )
 
+// setDensityFolders replaces the built-in density tables with a mapping of
+// folder name to Android dpi value (160 for mdpi, 240 for hdpi, ...).
+func setDensityFolders(folders map[string]float64) error {
+  if len(folders) == 0 {
+    return errors.New("no density folders configured")
+  }
+
+  toDensity := map[string]dpi{}
+  toFolder := map[dpi]string{}
+  toCanonical := map[dpi]string{}
+  var ascending []dpi
+  for folder, value := range folders {
+    if value <= 0 {
+      return fmt.Errorf("invalid density %v for folder %s", value, folder)
+    }
+    density := dpi(value / 40)
+    if other, ok := toFolder[density]; ok {
+      return fmt.Errorf("folders %s and %s have the same density", other, folder)
+    }
+    toDensity[folder] = density
+    toFolder[density] = folder
+    toCanonical[density] = folder[strings.LastIndex(folder, "-")+1:]
+    ascending = append(ascending, density)
+  }
+  sort.Slice(ascending, func(i, j int) bool { return ascending[i] < ascending[j] })
+
+  var priority []string
+  for i := len(ascending) - 1; i >= 0; i-- {
+    priority = append(priority, toFolder[ascending[i]])
+  }
+
+  folderToDensity = toDensity
+  densityToFolder = toFolder
+  densityToCanonical = toCanonical
+  ascendingDensityList = ascending
+  densityPriorityList = priority
+  return nil
+}
+
 func fileExists(file string) bool {
   fi, err := os.Stat(file)
   return err == nil && fi.Mode().IsRegular()
@@ -88,19 +140,92 @@ func pathExists(path string) bool {
   return err == nil
 }
 
-func guessResFolder() (folder string, err error) {
+func guessResFolders() (folders []string, err error) {
+  if len(resDirs) > 0 {
+    for _, dir := range resDirs {
+      if !dirExists(dir) {
+        return nil, fmt.Errorf("res folder %s not found", dir)
+      }
+      folders = append(folders, filepath.Clean(dir))
+    }
+    return folders, nil
+  }
+
   ResDirGuesses := []string{ "res", "src/main/res" }
-  for _, guess := range ResDirGuesses {
-    if dirExists(guess) {
-      return guess, nil
+  sets, _ := filepath.Glob(filepath.Join("src", "*", "res"))
+  sort.Strings(sets)
+  for _, guess := range append(ResDirGuesses, sets...) {
+    if dirExists(guess) && !containsString(folders, guess) {
+      folders = append(folders, filepath.Clean(guess))
     }
   }
 
-  return "", errors.New("no folder found")
+  if len(folders) == 0 {
+    return nil, errors.New("no folder found")
+  }
+  return folders, nil
+}
+
+func containsString(list []string, s string) bool {
+  for _, item := range list {
+    if filepath.Clean(item) == filepath.Clean(s) {
+      return true
+    }
+  }
+  return false
+}
+
+// sourceSetName returns the Gradle source set a res folder belongs to, e.g.
+// "paid" for src/paid/res. Plain "res" folders are treated as main.
+func sourceSetName(resFolder string) string {
+  parent := filepath.Dir(resFolder)
+  if filepath.Base(filepath.Dir(parent)) == "src" {
+    return filepath.Base(parent)
+  }
+  return "main"
+}
+
+func sourceSetResFolder(resFolder string, name string) string {
+  if sourceSetName(resFolder) == name {
+    return resFolder
+  }
+  parent := filepath.Dir(resFolder)
+  srcDir := filepath.Dir(parent)
+  if filepath.Base(srcDir) != "src" {
+    srcDir = filepath.Join(parent, "src")
+  }
+  return filepath.Join(srcDir, name, "res")
+}
+
+func targetResFolders(resFolder string, sourceSets []string) (folders []string) {
+  if outputOptions.Out != "" {
+    // --out replaces the source sets too, everything goes in one place
+    return []string{tryGetAbsPath(outputOptions.Out)}
+  }
+  if len(sourceSets) == 0 {
+    return []string{resFolder}
+  }
+  for _, name := range sourceSets {
+    if name == "all" {
+      guesses, _ := guessResFolders()
+      for _, guess := range guesses {
+        folders = append(folders, tryGetAbsPath(guess))
+      }
+      continue
+    }
+    folders = append(folders, sourceSetResFolder(resFolder, name))
+  }
+  return
 }
 
 func extractResFolder(path string) (folder string, err error) {
-  fmt.Printf("extractResFolder(\"%s\")\n", path)
+  for _, dir := range resDirs {
+    dir = tryGetAbsPath(dir)
+    if rel, relErr := filepath.Rel(dir, path); relErr == nil && rel != ".." && !strings.HasPrefix(rel, ".." + string(filepath.Separator)) {
+      return dir, nil
+    }
+  }
+
   folders := strings.Split(filepath.ToSlash(path), "/")
   for i, folder := range folders {
     if folder == "res" {
@@ -152,21 +277,79 @@ func getDrawableInfo(path string) (info DrawableInfo, err error) {
     if err != nil { return }
     _, filename = filepath.Split(path)
   } else {
-    resFolder, err = guessResFolder()
-    if err != nil { return }
-    density, err = findHighestDensity(resFolder, path)
+    var folders []string
+    folders, err = guessResFolders()
     if err != nil { return }
+    for _, folder := range folders {
+      if found, findErr := findHighestDensity(folder, path); findErr == nil && found > density {
+        resFolder, density = folder, found
+      }
+    }
+    if density == 0 {
+      return info, errors.New("no density found")
+    }
     filename = path
   }
 
   return DrawableInfo{ResFolder: tryGetAbsPath(resFolder), Filename: filename, Density: density}, nil
 }
 
+func openDrawable(path string) (info DrawableInfo, img image.Image, err error) {
+  info, err = getDrawableInfo(path)
+  if err != nil { return }
+  assetPath := filepath.Join(info.ResFolder, densityToFolder[info.Density], info.Filename)
+  printf("%s %s\n", green("from"), assetPath)
+  img, info.Format, err = decodeImageFile(assetPath)
+  if err != nil { return }
+  img, err = prepareMaster(img, info.Density)
+  return
+}
+
+// qualifiedFolder inserts a resource qualifier in front of the density
+// qualifier, e.g. drawable-xxhdpi + ldrtl = drawable-ldrtl-xxhdpi.
+func qualifiedFolder(folder string, qualifier string) string {
+  if qualifier == "" {
+    return folder
+  }
+  i := strings.LastIndex(folder, "-")
+  if i < 0 {
+    return folder + "-" + qualifier
+  }
+  return folder[:i] + "-" + qualifier + folder[i:]
+}
+
+func resourceFolder(resType string, density dpi) string {
+  if resType == "drawable" {
+    return densityToFolder[density]
+  }
+  return resType + "-" + densityToCanonical[density]
+}
+
+// writeDensitySet writes img at widthDp x heightDp into the resType folder of
+// every density.
+func writeDensitySet(resFolder string, resType string, filename string, img image.Image, widthDp float64, heightDp float64) error {
+  for _, density := range ascendingDensityList {
+    path := filepath.Join(resFolder, resourceFolder(resType, density), filename)
+    if skipExisting(path) {
+      continue
+    }
+    resized := resize.Resize(uint(dpToPx(widthDp, density)), uint(dpToPx(heightDp, density)), img, resize.Lanczos3)
+    if err := writeImage(path, resized, "png"); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+
+func dpToPx(dp float64, density dpi) int {
+  return int(math.Round(dp * float64(density) / MDPI))
+}
+
 func getDimens(img *image.Image) (width int, height int) {
   return (*img).Bounds().Max.X - (*img).Bounds().Min.X, (*img).Bounds().Max.Y - (*img).Bounds().Min.Y
 }
 
-func resizeToFolders(drawableInfo *DrawableInfo, img *image.Image) {
+func resizeToFolders(drawableInfo *DrawableInfo, img *image.Image) error {
   var startingDensity int
   for i, folder := range densityPriorityList {
     if (folderToDensity[folder] == (*drawableInfo).Density) {
@@ -177,70 +360,277 @@ func resizeToFolders(drawableInfo *DrawableInfo, img *image.Image) {
 
   if startingDensity < len(densityPriorityList) {
     for _, folder := range densityPriorityList[startingDensity:] {
-      resizeTo(drawableInfo, img, folder)
+      if err := resizeTo(drawableInfo, img, folder); err != nil {
+        return err
+      }
     }
   }
+  return nil
 }
 
-func resizeTo(drawableInfo *DrawableInfo, img *image.Image, folder string) {
+func resizeTo(drawableInfo *DrawableInfo, img *image.Image, folder string) error {
   targetDensity := folderToDensity[folder]
-  targetPath := filepath.Join((*drawableInfo).ResFolder, folder, (*drawableInfo).Filename)
-  width, _ := getDimens(img)
-  resized := resize.Resize(uint(float64(width)*float64(targetDensity)/float64((*drawableInfo).Density)), 0, *img, resize.Lanczos3)
-  out, err := os.Create(targetPath)
-  if err != nil {
-    log.Fatal(err)
+  filename := withFormatExtension((*drawableInfo).Filename, (*drawableInfo).Format)
+  targetPath := filepath.Join((*drawableInfo).ResFolder, qualifiedFolder(folder, (*drawableInfo).Qualifier), filename)
+  if (*drawableInfo).Skip[targetDensity] || skipExistingOutput((*drawableInfo).Asset, targetPath) {
+    return nil
   }
-  defer out.Close()
+  width, height := getDimens(img)
+  resized := *img
+  if targetDensity != (*drawableInfo).Density && (*drawableInfo).NinePatch {
+    targetWidth, _ := scaleDimension(width - 2, (*drawableInfo).Density, targetDensity)
+    targetHeight, _ := scaleDimension(height - 2, (*drawableInfo).Density, targetDensity)
+    resized = resizeNinePatch(*img, targetWidth, targetHeight)
+  } else if targetDensity != (*drawableInfo).Density {
+    targetWidth, exactWidth := scaleDimension(width, (*drawableInfo).Density, targetDensity)
+    targetHeight, exactHeight := scaleDimension(height, (*drawableInfo).Density, targetDensity)
+    if math.Abs(float64(targetWidth)-exactWidth) > outputOptions.RoundingWarn || math.Abs(float64(targetHeight)-exactHeight) > outputOptions.RoundingWarn {
+      printf("  %s %s is %.2fx%.2fpx, rounded to %dx%d\n", yellow("warn"), targetPath, exactWidth, exactHeight, targetWidth, targetHeight)
+    }
+    resized = resize.Resize(uint(targetWidth), uint(targetHeight), *img, resizeFilter())
+  }
+
+  return writeOutputImage((*drawableInfo).Asset, targetPath, resized, (*drawableInfo).Format)
+}
 
-  png.Encode(out, resized)
-  fmt.Printf("  %s %s\n", green("->"), targetPath)
+// variantToFolders writes img into the qualified variant of the source density
+// folder and every lower density, e.g. drawable-night-xxhdpi and below. An
+// empty qualifier writes into the plain density folders.
+func variantToFolders(drawableInfo *DrawableInfo, img *image.Image, qualifier string) error {
+  variant := *drawableInfo
+  variant.Qualifier = qualifier
+  if err := resizeTo(&variant, img, densityToFolder[variant.Density]); err != nil {
+    return err
+  }
+  return resizeToFolders(&variant, img)
 }
 
 func main() {
+  var sourceSets []string
+  var rtl, night, preserveFormat bool
+  var jobs int
+  var noCache bool
+  var nightSource, nightTransform string
+  var sourceDensity, stdoutDensity string
+  var format, profile, filter string
+  var target, pubspec string
+  var quality int
+  var only []string
   var dpitizeCmd = &cobra.Command{
     Use: "dpi [assets]",
     Short: "Take one or more assets and resize it for various densities.",
+    Long: `Take one or more assets and resize it for various densities.
+
+Assets can also be folders: every image in a density folder, or the highest
+density of every drawable in a res folder, is processed. An asset of - reads
+the image from stdin and writes a single density of it to stdout.
+
+--target flutter writes the 1x, 2.0x and 3.0x images Flutter looks for into
+--out (assets by default) instead of the res folder, and --pubspec lists them
+in the pubspec.yaml. --target react-native writes foo.png, foo@2x.png and
+foo@3x.png into --out, and --target compose the drawable-<density> folders of
+a Compose Multiplatform composeResources folder.
+
+  andy dpi ic_hero.png
+  andy dpi res/drawable-xxxhdpi/
+  andy dpi ic_hero.png --target flutter --out flutter_module/assets --pubspec flutter_module/pubspec.yaml
+  cat icon.png | andy dpi - --source-density xxxhdpi --stdout-density hdpi > out.png`,
     Run: func(cmd *cobra.Command, args []string) {
       if len(args) < 1 {
         log.Fatal("need one or more filenames.")
       }
-      for _, arg := range args {
-        drawableInfo, err := getDrawableInfo(arg)
-        if err != nil {
-          log.Fatal(err)
+      if err := checkOutputOptions(); err != nil {
+        log.Fatal(err)
+      }
+      if !cmd.Flags().Changed("format") && config.Format != "" {
+        format = config.Format
+      }
+      if _, ok := formatExtensions[format]; !ok {
+        log.Fatalf("unknown --format %q, expected png, webp or jpeg", format)
+      }
+      if _, ok := resizeFilters[filter]; !ok {
+        log.Fatalf("unknown --filter %q", filter)
+      }
+      if cmd.Flags().Changed("quality") {
+        if err := setEncodeOptions(quality, ""); err != nil { log.Fatal(err) }
+      }
+      outputOptions.Filter = filter
+      if err := skipUnlisted(map[dpi]bool{}, only); err != nil { log.Fatal(err) }
+      if err := checkTarget(target); err != nil { log.Fatal(err) }
+      if target != "android" && (rtl || night || nightSource != "" || cmd.Flags().Changed("source-set")) {
+        log.Fatal("--rtl, --night and --source-set only work with --target android.")
+      }
+      if pubspec != "" && target != "flutter" {
+        log.Fatal("--pubspec only works with --target flutter.")
+      }
+      if len(args) == 1 && args[0] == "-" {
+        if sourceDensity == "" || stdoutDensity == "" {
+          log.Fatal("reading from stdin needs --source-density and --stdout-density.")
+        }
+        if rtl || night || nightSource != "" {
+          log.Fatal("--rtl and --night don't work with stdin, there's only one output.")
         }
-        assetPath := filepath.Join(drawableInfo.ResFolder, densityToFolder[drawableInfo.Density], drawableInfo.Filename)
-        fmt.Printf("%s %s\n", green("from"), assetPath)
-        file, err := os.Open(assetPath)
+        from, err := parseDensity(sourceDensity)
         if err != nil { log.Fatal(err) }
-
-        img, err := png.Decode(file)
+        to, err := parseDensity(stdoutDensity)
         if err != nil { log.Fatal(err) }
-        file.Close()
+        if preserveFormat {
+          format = ""
+        }
+        if err := dpiStream(os.Stdin, os.Stdout, from, to, format); err != nil { log.Fatal(err) }
+        return
+      }
+      if !cmd.Flags().Changed("source-set") {
+        sourceSets = config.SourceSets
+      }
+      if !cmd.Flags().Changed("night-transform") && config.NightTransform != "" {
+        nightTransform = config.NightTransform
+      }
+      night = night || nightSource != "" || cmd.Flags().Changed("night-transform")
+      if _, err := parseNightTransform(nightTransform); err != nil { log.Fatal(err) }
+      assets, err := expandAssetArgs(args)
+      if err != nil { log.Fatal(err) }
+      if nightSource != "" && len(assets) > 1 {
+        log.Fatal("--night-source only works with a single asset.")
+      }
+      if jobs < 1 {
+        log.Fatal("--jobs must be at least 1.")
+      }
+      if len(sourceSets) == 0 {
+        sourceSets = nil
+      }
+      options := DpiOptions{SourceSets: sourceSets, PreserveFormat: preserveFormat, RTL: rtl, Rounding: outputOptions.Rounding, Master: masterOptions, Densities: densityValues()}
+      if format != "png" {
+        options.Format = format
+      }
+      if filter != "lanczos3" {
+        options.Filter = filter
+      }
+      if len(only) > 0 {
+        options.Only = only
+      }
+      if format == "jpeg" || preserveFormat || cmd.Flags().Changed("quality") {
+        options.Quality = encodeQuality
+      }
+      if outputOptions.Out != "" {
+        options.Out = projectPath(outputOptions.Out)
+      }
+      if target != "android" {
+        options.Target = target
+      }
+      if night {
+        options.Night, options.NightTransform = true, nightTransform
+      }
+      if nightSource != "" {
+        options.NightSource = projectPath(nightSource)
+        if options.NightSourceHash, err = fileHash(nightSource); err != nil { log.Fatal(err) }
+        options.NightTransform = ""
+      }
+      if err := loadLock(); err != nil { log.Fatal(err) }
+      failures := dpitize(assets, options, jobs, noCache)
+      if pubspec != "" && len(targetAssets) > 0 {
+        if err := patchPubspec(pubspec, targetAssets); err != nil { log.Fatal(err) }
+      }
 
-        resizeToFolders(&drawableInfo, &img)
+      if len(assets) > 1 {
+        printSummary(assets, failures)
+      } else if failures[0] != nil {
+        os.Exit(1)
       }
     },
   }
-
-  var convertCmd = &cobra.Command{
-    Use: "convert [unit]",
-    Short: "Convert a density-independent unit to its corresponding pixel sizes per density.",
-    Run: func(cmd *cobra.Command, args []string) {
-      if len(args) != 1 {
-        log.Fatal("pass in one unit measurement, please. ex: 30dp")
+  addOutputFlags(dpitizeCmd)
+  addMasterFlags(dpitizeCmd)
+  dpitizeCmd.Flags().StringSliceVarP(&sourceSets, "source-set", "s", nil, "source sets to write generated assets into (e.g. main,paid or all)")
+  dpitizeCmd.Flags().StringVar(&sourceDensity, "source-density", "", "density of the image read from stdin with -")
+  dpitizeCmd.Flags().StringVar(&stdoutDensity, "stdout-density", "", "density to write to stdout when reading from stdin with -")
+  dpitizeCmd.Flags().BoolVar(&noCache, "no-cache", false, "regenerate even if andy.lock says the source and options haven't changed")
+  dpitizeCmd.Flags().IntVarP(&jobs, "jobs", "j", runtime.NumCPU(), "number of assets to process at once")
+  dpitizeCmd.Flags().BoolVar(&rtl, "rtl", false, "also generate mirrored drawable-ldrtl-* variants")
+  dpitizeCmd.Flags().StringVar(&profile, "profile", "", "named set of options from [profiles] in the config")
+  dpitizeCmd.Flags().StringVar(&format, "format", "png", "format of the generated assets: png, webp or jpeg")
+  dpitizeCmd.Flags().IntVar(&quality, "quality", 0, "jpeg quality, 1 to 100 (default from the config, or 90)")
+  dpitizeCmd.Flags().StringVar(&filter, "filter", "lanczos3", "resampling filter: lanczos3, lanczos2, mitchell, bicubic, bilinear or nearest")
+  dpitizeCmd.Flags().StringSliceVar(&only, "only", nil, "only generate these densities (e.g. xhdpi,xxhdpi)")
+  dpitizeCmd.Flags().BoolVar(&preserveFormat, "preserve-format", false, "write generated assets in the source's format (jpeg, webp) instead of png")
+  dpitizeCmd.Flags().StringVar(&target, "target", "android", "platform to write the densities for: android, flutter, react-native or compose")
+  dpitizeCmd.Flags().StringVar(&pubspec, "pubspec", "", "pubspec.yaml to list the generated flutter assets in")
+  dpitizeCmd.Flags().BoolVar(&night, "night", false, "also generate drawable-night-* variants")
+  dpitizeCmd.Flags().StringVar(&nightSource, "night-source", "", "image to use for the night variants instead of transforming the asset")
+  dpitizeCmd.Flags().StringVar(&nightTransform, "night-transform", "invert", "transform for night variants: invert, tint:#RRGGBB or brightness:<factor>")
+
+  var rootCmd = &cobra.Command{
+    Use: "andy",
+    PersistentPreRun: func(cmd *cobra.Command, args []string) {
+      if env := os.Getenv("ANDY_RES_DIR"); env != "" && !cmd.Flags().Changed("res-dir") {
+        resDirs = filepath.SplitList(env)
       }
-      dpRegex := regexp.MustCompile(`(\d+\.?\d*)dp`)
-      dpValue, _ := strconv.ParseFloat(dpRegex.FindStringSubmatch(args[0])[1], 0)
-      for _, density := range ascendingDensityList {
-        fmt.Printf("  %8s: %.1fpx\n", densityToCanonical[density], float64(dpValue) / float64(MDPI) * float64(density))
+      if configPath == "" {
+        if configPath = findConfig(); configPath == "" {
+          if flag := cmd.Flags().Lookup("profile"); flag != nil && flag.Changed {
+            log.Fatal("--profile needs an andy.toml or andy.yaml defining it.")
+          }
+          return
+        }
+      } else if !fileExists(configPath) {
+        log.Fatalf("config file %s not found", configPath)
+      }
+      if err := loadConfig(configPath); err != nil {
+        log.Fatal(err)
+      }
+      if len(resDirs) == 0 {
+        resDirs = config.ResDirs
+      }
+      if err := applyCommandDefaults(cmd); err != nil {
+        log.Fatal(err)
       }
     },
   }
-
-  var rootCmd = &cobra.Command{Use: "andy"}
+  rootCmd.PersistentFlags().StringSliceVar(&resDirs, "res-dir", nil, "res folder(s) to use instead of guessing (or set ANDY_RES_DIR)")
+  rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "path to the andy config file (default andy.toml or andy.yaml)")
   rootCmd.AddCommand(dpitizeCmd)
   rootCmd.AddCommand(convertCmd)
-  rootCmd.Execute()
+  rootCmd.AddCommand(mirrorCmd)
+  rootCmd.AddCommand(iconCmd)
+  rootCmd.AddCommand(notifyCmd)
+  rootCmd.AddCommand(bannerCmd)
+  rootCmd.AddCommand(splashCmd)
+  rootCmd.AddCommand(shortcutCmd)
+  rootCmd.AddCommand(widgetPreviewCmd)
+  rootCmd.AddCommand(placeholderCmd)
+  rootCmd.AddCommand(ribbonCmd)
+  rootCmd.AddCommand(tintCmd)
+  rootCmd.AddCommand(composeCmd)
+  rootCmd.AddCommand(cropCmd)
+  rootCmd.AddCommand(dimensCmd)
+  rootCmd.AddCommand(screenCmd)
+  rootCmd.AddCommand(sizeCmd)
+  rootCmd.AddCommand(scalesCmd)
+  rootCmd.AddCommand(checkCmd)
+  rootCmd.AddCommand(densityCmd)
+  rootCmd.AddCommand(watchCmd)
+  rootCmd.AddCommand(regenCmd)
+  rootCmd.AddCommand(undoCmd)
+  rootCmd.AddCommand(lsCmd)
+  rootCmd.AddCommand(auditCmd)
+  rootCmd.AddCommand(dupesCmd)
+  rootCmd.AddCommand(unusedCmd)
+  rootCmd.AddCommand(rmCmd)
+  rootCmd.AddCommand(mvCmd)
+  rootCmd.AddCommand(cpCmd)
+  rootCmd.AddCommand(diffCmd)
+  rootCmd.AddCommand(mergeCmd)
+  rootCmd.AddCommand(budgetCmd)
+  rootCmd.AddCommand(vectorsCmd)
+  rootCmd.AddCommand(estimateCmd)
+  rootCmd.AddCommand(graphCmd)
+  rootCmd.AddCommand(pullCmd)
+  rootCmd.AddCommand(importCmd)
+  rootCmd.AddCommand(exportCmd)
+  rootCmd.AddCommand(gradleInitCmd)
+  rootCmd.AddCommand(hookCmd)
+  rootCmd.AddCommand(serveCmd)
+  if err := rootCmd.Execute(); err != nil {
+    os.Exit(1)
+  }
 }
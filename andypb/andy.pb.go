@@ -0,0 +1,798 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: andy.proto
+
+package andypb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Master struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// resource name, ending in .9 for a nine-patch; image if empty
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// density the master was drawn at, e.g. xxxhdpi
+	Density string `protobuf:"bytes,2,opt,name=density,proto3" json:"density,omitempty"`
+	// png, webp or jpeg, the master's format if empty
+	Format        string `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Master) Reset() {
+	*x = Master{}
+	mi := &file_andy_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Master) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Master) ProtoMessage() {}
+
+func (x *Master) ProtoReflect() protoreflect.Message {
+	mi := &file_andy_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Master.ProtoReflect.Descriptor instead.
+func (*Master) Descriptor() ([]byte, []int) {
+	return file_andy_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Master) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Master) GetDensity() string {
+	if x != nil {
+		return x.Density
+	}
+	return ""
+}
+
+func (x *Master) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+// Upload is the master first, then the chunks of its image.
+type Upload struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Part:
+	//
+	//	*Upload_Master
+	//	*Upload_Chunk
+	Part          isUpload_Part `protobuf_oneof:"part"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Upload) Reset() {
+	*x = Upload{}
+	mi := &file_andy_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Upload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Upload) ProtoMessage() {}
+
+func (x *Upload) ProtoReflect() protoreflect.Message {
+	mi := &file_andy_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Upload.ProtoReflect.Descriptor instead.
+func (*Upload) Descriptor() ([]byte, []int) {
+	return file_andy_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Upload) GetPart() isUpload_Part {
+	if x != nil {
+		return x.Part
+	}
+	return nil
+}
+
+func (x *Upload) GetMaster() *Master {
+	if x != nil {
+		if x, ok := x.Part.(*Upload_Master); ok {
+			return x.Master
+		}
+	}
+	return nil
+}
+
+func (x *Upload) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Part.(*Upload_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isUpload_Part interface {
+	isUpload_Part()
+}
+
+type Upload_Master struct {
+	Master *Master `protobuf:"bytes,1,opt,name=master,proto3,oneof"`
+}
+
+type Upload_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*Upload_Master) isUpload_Part() {}
+
+func (*Upload_Chunk) isUpload_Part() {}
+
+type Image struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// path in a res folder, e.g. drawable-xhdpi/ic_logo.png
+	Path          string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Density       string `protobuf:"bytes,2,opt,name=density,proto3" json:"density,omitempty"`
+	Width         int32  `protobuf:"varint,3,opt,name=width,proto3" json:"width,omitempty"`
+	Height        int32  `protobuf:"varint,4,opt,name=height,proto3" json:"height,omitempty"`
+	Bytes         int64  `protobuf:"varint,5,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Image) Reset() {
+	*x = Image{}
+	mi := &file_andy_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Image) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Image) ProtoMessage() {}
+
+func (x *Image) ProtoReflect() protoreflect.Message {
+	mi := &file_andy_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Image.ProtoReflect.Descriptor instead.
+func (*Image) Descriptor() ([]byte, []int) {
+	return file_andy_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Image) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Image) GetDensity() string {
+	if x != nil {
+		return x.Density
+	}
+	return ""
+}
+
+func (x *Image) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *Image) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Image) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+// Generated is an image, then the chunks of its file.
+type Generated struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Part:
+	//
+	//	*Generated_Image
+	//	*Generated_Chunk
+	Part          isGenerated_Part `protobuf_oneof:"part"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Generated) Reset() {
+	*x = Generated{}
+	mi := &file_andy_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Generated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Generated) ProtoMessage() {}
+
+func (x *Generated) ProtoReflect() protoreflect.Message {
+	mi := &file_andy_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Generated.ProtoReflect.Descriptor instead.
+func (*Generated) Descriptor() ([]byte, []int) {
+	return file_andy_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Generated) GetPart() isGenerated_Part {
+	if x != nil {
+		return x.Part
+	}
+	return nil
+}
+
+func (x *Generated) GetImage() *Image {
+	if x != nil {
+		if x, ok := x.Part.(*Generated_Image); ok {
+			return x.Image
+		}
+	}
+	return nil
+}
+
+func (x *Generated) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Part.(*Generated_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isGenerated_Part interface {
+	isGenerated_Part()
+}
+
+type Generated_Image struct {
+	Image *Image `protobuf:"bytes,1,opt,name=image,proto3,oneof"`
+}
+
+type Generated_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*Generated_Image) isGenerated_Part() {}
+
+func (*Generated_Chunk) isGenerated_Part() {}
+
+type ConvertRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Values []string               `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	// densities to convert for, or the one px values were measured at
+	At []string `protobuf:"bytes,2,rep,name=at,proto3" json:"at,omitempty"`
+	// units to show per density, px if empty
+	To            []string `protobuf:"bytes,3,rep,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConvertRequest) Reset() {
+	*x = ConvertRequest{}
+	mi := &file_andy_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvertRequest) ProtoMessage() {}
+
+func (x *ConvertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_andy_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvertRequest.ProtoReflect.Descriptor instead.
+func (*ConvertRequest) Descriptor() ([]byte, []int) {
+	return file_andy_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ConvertRequest) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *ConvertRequest) GetAt() []string {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+func (x *ConvertRequest) GetTo() []string {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type Conversion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Dp            float64                `protobuf:"fixed64,2,opt,name=dp,proto3" json:"dp,omitempty"`
+	Densities     []*DensityValues       `protobuf:"bytes,3,rep,name=densities,proto3" json:"densities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Conversion) Reset() {
+	*x = Conversion{}
+	mi := &file_andy_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Conversion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Conversion) ProtoMessage() {}
+
+func (x *Conversion) ProtoReflect() protoreflect.Message {
+	mi := &file_andy_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Conversion.ProtoReflect.Descriptor instead.
+func (*Conversion) Descriptor() ([]byte, []int) {
+	return file_andy_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Conversion) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Conversion) GetDp() float64 {
+	if x != nil {
+		return x.Dp
+	}
+	return 0
+}
+
+func (x *Conversion) GetDensities() []*DensityValues {
+	if x != nil {
+		return x.Densities
+	}
+	return nil
+}
+
+type DensityValues struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Density       string                 `protobuf:"bytes,1,opt,name=density,proto3" json:"density,omitempty"`
+	Dpi           float64                `protobuf:"fixed64,2,opt,name=dpi,proto3" json:"dpi,omitempty"`
+	Values        map[string]float64     `protobuf:"bytes,3,rep,name=values,proto3" json:"values,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DensityValues) Reset() {
+	*x = DensityValues{}
+	mi := &file_andy_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DensityValues) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DensityValues) ProtoMessage() {}
+
+func (x *DensityValues) ProtoReflect() protoreflect.Message {
+	mi := &file_andy_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DensityValues.ProtoReflect.Descriptor instead.
+func (*DensityValues) Descriptor() ([]byte, []int) {
+	return file_andy_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DensityValues) GetDensity() string {
+	if x != nil {
+		return x.Density
+	}
+	return ""
+}
+
+func (x *DensityValues) GetDpi() float64 {
+	if x != nil {
+		return x.Dpi
+	}
+	return 0
+}
+
+func (x *DensityValues) GetValues() map[string]float64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type ConvertResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Conversions   []*Conversion          `protobuf:"bytes,1,rep,name=conversions,proto3" json:"conversions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConvertResponse) Reset() {
+	*x = ConvertResponse{}
+	mi := &file_andy_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvertResponse) ProtoMessage() {}
+
+func (x *ConvertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_andy_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvertResponse.ProtoReflect.Descriptor instead.
+func (*ConvertResponse) Descriptor() ([]byte, []int) {
+	return file_andy_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ConvertResponse) GetConversions() []*Conversion {
+	if x != nil {
+		return x.Conversions
+	}
+	return nil
+}
+
+type Finding struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Check         string                 `protobuf:"bytes,1,opt,name=check,proto3" json:"check,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Finding) Reset() {
+	*x = Finding{}
+	mi := &file_andy_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Finding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Finding) ProtoMessage() {}
+
+func (x *Finding) ProtoReflect() protoreflect.Message {
+	mi := &file_andy_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Finding.ProtoReflect.Descriptor instead.
+func (*Finding) Descriptor() ([]byte, []int) {
+	return file_andy_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Finding) GetCheck() string {
+	if x != nil {
+		return x.Check
+	}
+	return ""
+}
+
+func (x *Finding) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Finding) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type CheckResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Checked       int32                  `protobuf:"varint,1,opt,name=checked,proto3" json:"checked,omitempty"`
+	Findings      []*Finding             `protobuf:"bytes,2,rep,name=findings,proto3" json:"findings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckResponse) Reset() {
+	*x = CheckResponse{}
+	mi := &file_andy_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckResponse) ProtoMessage() {}
+
+func (x *CheckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_andy_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckResponse.ProtoReflect.Descriptor instead.
+func (*CheckResponse) Descriptor() ([]byte, []int) {
+	return file_andy_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CheckResponse) GetChecked() int32 {
+	if x != nil {
+		return x.Checked
+	}
+	return 0
+}
+
+func (x *CheckResponse) GetFindings() []*Finding {
+	if x != nil {
+		return x.Findings
+	}
+	return nil
+}
+
+var File_andy_proto protoreflect.FileDescriptor
+
+const file_andy_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"andy.proto\x12\x04andy\"N\n" +
+	"\x06Master\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\adensity\x18\x02 \x01(\tR\adensity\x12\x16\n" +
+	"\x06format\x18\x03 \x01(\tR\x06format\"P\n" +
+	"\x06Upload\x12&\n" +
+	"\x06master\x18\x01 \x01(\v2\f.andy.MasterH\x00R\x06master\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\x06\n" +
+	"\x04part\"y\n" +
+	"\x05Image\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x18\n" +
+	"\adensity\x18\x02 \x01(\tR\adensity\x12\x14\n" +
+	"\x05width\x18\x03 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\x04 \x01(\x05R\x06height\x12\x14\n" +
+	"\x05bytes\x18\x05 \x01(\x03R\x05bytes\"P\n" +
+	"\tGenerated\x12#\n" +
+	"\x05image\x18\x01 \x01(\v2\v.andy.ImageH\x00R\x05image\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\x06\n" +
+	"\x04part\"H\n" +
+	"\x0eConvertRequest\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\tR\x06values\x12\x0e\n" +
+	"\x02at\x18\x02 \x03(\tR\x02at\x12\x0e\n" +
+	"\x02to\x18\x03 \x03(\tR\x02to\"e\n" +
+	"\n" +
+	"Conversion\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12\x0e\n" +
+	"\x02dp\x18\x02 \x01(\x01R\x02dp\x121\n" +
+	"\tdensities\x18\x03 \x03(\v2\x13.andy.DensityValuesR\tdensities\"\xaf\x01\n" +
+	"\rDensityValues\x12\x18\n" +
+	"\adensity\x18\x01 \x01(\tR\adensity\x12\x10\n" +
+	"\x03dpi\x18\x02 \x01(\x01R\x03dpi\x127\n" +
+	"\x06values\x18\x03 \x03(\v2\x1f.andy.DensityValues.ValuesEntryR\x06values\x1a9\n" +
+	"\vValuesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"E\n" +
+	"\x0fConvertResponse\x122\n" +
+	"\vconversions\x18\x01 \x03(\v2\x10.andy.ConversionR\vconversions\"M\n" +
+	"\aFinding\x12\x14\n" +
+	"\x05check\x18\x01 \x01(\tR\x05check\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"T\n" +
+	"\rCheckResponse\x12\x18\n" +
+	"\achecked\x18\x01 \x01(\x05R\achecked\x12)\n" +
+	"\bfindings\x18\x02 \x03(\v2\r.andy.FindingR\bfindings2\x96\x01\n" +
+	"\x04Andy\x12(\n" +
+	"\x03Dpi\x12\f.andy.Upload\x1a\x0f.andy.Generated(\x010\x01\x126\n" +
+	"\aConvert\x12\x14.andy.ConvertRequest\x1a\x15.andy.ConvertResponse\x12,\n" +
+	"\x05Check\x12\f.andy.Upload\x1a\x13.andy.CheckResponse(\x01B Z\x1egithub.com/mcginty/andy/andypbb\x06proto3"
+
+var (
+	file_andy_proto_rawDescOnce sync.Once
+	file_andy_proto_rawDescData []byte
+)
+
+func file_andy_proto_rawDescGZIP() []byte {
+	file_andy_proto_rawDescOnce.Do(func() {
+		file_andy_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_andy_proto_rawDesc), len(file_andy_proto_rawDesc)))
+	})
+	return file_andy_proto_rawDescData
+}
+
+var file_andy_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_andy_proto_goTypes = []any{
+	(*Master)(nil),          // 0: andy.Master
+	(*Upload)(nil),          // 1: andy.Upload
+	(*Image)(nil),           // 2: andy.Image
+	(*Generated)(nil),       // 3: andy.Generated
+	(*ConvertRequest)(nil),  // 4: andy.ConvertRequest
+	(*Conversion)(nil),      // 5: andy.Conversion
+	(*DensityValues)(nil),   // 6: andy.DensityValues
+	(*ConvertResponse)(nil), // 7: andy.ConvertResponse
+	(*Finding)(nil),         // 8: andy.Finding
+	(*CheckResponse)(nil),   // 9: andy.CheckResponse
+	nil,                     // 10: andy.DensityValues.ValuesEntry
+}
+var file_andy_proto_depIdxs = []int32{
+	0,  // 0: andy.Upload.master:type_name -> andy.Master
+	2,  // 1: andy.Generated.image:type_name -> andy.Image
+	6,  // 2: andy.Conversion.densities:type_name -> andy.DensityValues
+	10, // 3: andy.DensityValues.values:type_name -> andy.DensityValues.ValuesEntry
+	5,  // 4: andy.ConvertResponse.conversions:type_name -> andy.Conversion
+	8,  // 5: andy.CheckResponse.findings:type_name -> andy.Finding
+	1,  // 6: andy.Andy.Dpi:input_type -> andy.Upload
+	4,  // 7: andy.Andy.Convert:input_type -> andy.ConvertRequest
+	1,  // 8: andy.Andy.Check:input_type -> andy.Upload
+	3,  // 9: andy.Andy.Dpi:output_type -> andy.Generated
+	7,  // 10: andy.Andy.Convert:output_type -> andy.ConvertResponse
+	9,  // 11: andy.Andy.Check:output_type -> andy.CheckResponse
+	9,  // [9:12] is the sub-list for method output_type
+	6,  // [6:9] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_andy_proto_init() }
+func file_andy_proto_init() {
+	if File_andy_proto != nil {
+		return
+	}
+	file_andy_proto_msgTypes[1].OneofWrappers = []any{
+		(*Upload_Master)(nil),
+		(*Upload_Chunk)(nil),
+	}
+	file_andy_proto_msgTypes[3].OneofWrappers = []any{
+		(*Generated_Image)(nil),
+		(*Generated_Chunk)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_andy_proto_rawDesc), len(file_andy_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_andy_proto_goTypes,
+		DependencyIndexes: file_andy_proto_depIdxs,
+		MessageInfos:      file_andy_proto_msgTypes,
+	}.Build()
+	File_andy_proto = out.File
+	file_andy_proto_goTypes = nil
+	file_andy_proto_depIdxs = nil
+}
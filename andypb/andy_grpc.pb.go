@@ -0,0 +1,203 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: andy.proto
+
+package andypb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Andy_Dpi_FullMethodName     = "/andy.Andy/Dpi"
+	Andy_Convert_FullMethodName = "/andy.Andy/Convert"
+	Andy_Check_FullMethodName   = "/andy.Andy/Check"
+)
+
+// AndyClient is the client API for Andy service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Andy is what andy serve --grpc-port offers, the same as its HTTP endpoints.
+// Masters and generated images are streamed in chunks, so large ones don't
+// run into message size limits.
+type AndyClient interface {
+	// Dpi takes the master's options and then the master in chunks, and
+	// streams back each generated density as its image followed by its chunks.
+	Dpi(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Upload, Generated], error)
+	// Convert converts values like andy convert --output json.
+	Convert(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*ConvertResponse, error)
+	// Check takes a master like Dpi does and returns what andy check <image>
+	// finds.
+	Check(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[Upload, CheckResponse], error)
+}
+
+type andyClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAndyClient(cc grpc.ClientConnInterface) AndyClient {
+	return &andyClient{cc}
+}
+
+func (c *andyClient) Dpi(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[Upload, Generated], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Andy_ServiceDesc.Streams[0], Andy_Dpi_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[Upload, Generated]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Andy_DpiClient = grpc.BidiStreamingClient[Upload, Generated]
+
+func (c *andyClient) Convert(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*ConvertResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConvertResponse)
+	err := c.cc.Invoke(ctx, Andy_Convert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *andyClient) Check(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[Upload, CheckResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Andy_ServiceDesc.Streams[1], Andy_Check_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[Upload, CheckResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Andy_CheckClient = grpc.ClientStreamingClient[Upload, CheckResponse]
+
+// AndyServer is the server API for Andy service.
+// All implementations must embed UnimplementedAndyServer
+// for forward compatibility.
+//
+// Andy is what andy serve --grpc-port offers, the same as its HTTP endpoints.
+// Masters and generated images are streamed in chunks, so large ones don't
+// run into message size limits.
+type AndyServer interface {
+	// Dpi takes the master's options and then the master in chunks, and
+	// streams back each generated density as its image followed by its chunks.
+	Dpi(grpc.BidiStreamingServer[Upload, Generated]) error
+	// Convert converts values like andy convert --output json.
+	Convert(context.Context, *ConvertRequest) (*ConvertResponse, error)
+	// Check takes a master like Dpi does and returns what andy check <image>
+	// finds.
+	Check(grpc.ClientStreamingServer[Upload, CheckResponse]) error
+	mustEmbedUnimplementedAndyServer()
+}
+
+// UnimplementedAndyServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAndyServer struct{}
+
+func (UnimplementedAndyServer) Dpi(grpc.BidiStreamingServer[Upload, Generated]) error {
+	return status.Error(codes.Unimplemented, "method Dpi not implemented")
+}
+func (UnimplementedAndyServer) Convert(context.Context, *ConvertRequest) (*ConvertResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Convert not implemented")
+}
+func (UnimplementedAndyServer) Check(grpc.ClientStreamingServer[Upload, CheckResponse]) error {
+	return status.Error(codes.Unimplemented, "method Check not implemented")
+}
+func (UnimplementedAndyServer) mustEmbedUnimplementedAndyServer() {}
+func (UnimplementedAndyServer) testEmbeddedByValue()              {}
+
+// UnsafeAndyServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AndyServer will
+// result in compilation errors.
+type UnsafeAndyServer interface {
+	mustEmbedUnimplementedAndyServer()
+}
+
+func RegisterAndyServer(s grpc.ServiceRegistrar, srv AndyServer) {
+	// If the following call panics, it indicates UnimplementedAndyServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Andy_ServiceDesc, srv)
+}
+
+func _Andy_Dpi_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AndyServer).Dpi(&grpc.GenericServerStream[Upload, Generated]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Andy_DpiServer = grpc.BidiStreamingServer[Upload, Generated]
+
+func _Andy_Convert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConvertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AndyServer).Convert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Andy_Convert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AndyServer).Convert(ctx, req.(*ConvertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Andy_Check_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AndyServer).Check(&grpc.GenericServerStream[Upload, CheckResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Andy_CheckServer = grpc.ClientStreamingServer[Upload, CheckResponse]
+
+// Andy_ServiceDesc is the grpc.ServiceDesc for Andy service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Andy_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "andy.Andy",
+	HandlerType: (*AndyServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Convert",
+			Handler:    _Andy_Convert_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Dpi",
+			Handler:       _Andy_Dpi_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Check",
+			Handler:       _Andy_Check_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "andy.proto",
+}
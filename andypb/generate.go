@@ -0,0 +1,5 @@
+// Package andypb is the gRPC interface of andy serve, generated from
+// andy.proto.
+package andypb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative andy.proto
@@ -0,0 +1,67 @@
+package main
+
+import (
+  "fmt"
+  "path/filepath"
+  "github.com/BurntSushi/toml"
+)
+
+// sidecarExt is the extension of an asset's own settings file, icon.png.andy
+// for icon.png.
+const sidecarExt = ".andy"
+
+// AssetConfig overrides settings for a single asset, from its sidecar file
+// or an [assets."<filename>"] table in the config.
+type AssetConfig struct {
+  Format string `toml:"format,omitempty" yaml:"format"`
+  NinePatch bool `toml:"nine_patch,omitempty" yaml:"nine_patch"`
+  // Skip lists densities not to generate, e.g. ["mdpi"]
+  Skip []string `toml:"skip,omitempty" yaml:"skip"`
+}
+
+// assetConfig is the config entry for the asset at path, keyed by its
+// filename or its path from the working directory, with its sidecar on top.
+func assetConfig(path string) (asset AssetConfig, err error) {
+  if entry, ok := config.Assets[projectPath(path)]; ok {
+    asset = entry
+  } else if entry, ok := config.Assets[filepath.Base(path)]; ok {
+    asset = entry
+  }
+  if sidecar := path + sidecarExt; fileExists(sidecar) {
+    var override AssetConfig
+    if _, err = toml.DecodeFile(sidecar, &override); err != nil {
+      return asset, fmt.Errorf("%s: %v", sidecar, err)
+    }
+    if override.Format != "" {
+      asset.Format = override.Format
+    }
+    if override.NinePatch {
+      asset.NinePatch = true
+    }
+    if override.Skip != nil {
+      asset.Skip = override.Skip
+    }
+  }
+  if len(asset.Skip) == 0 {
+    asset.Skip = nil
+  }
+  if asset.Format != "" {
+    if _, ok := formatExtensions[asset.Format]; !ok {
+      return asset, fmt.Errorf("%s: unknown format %q, expected png, webp or jpeg", path, asset.Format)
+    }
+  }
+  return asset, nil
+}
+
+// skipDensities parses the densities an asset shouldn't be generated at.
+func (asset AssetConfig) skipDensities() (map[dpi]bool, error) {
+  skip := map[dpi]bool{}
+  for _, name := range asset.Skip {
+    density, err := parseDensity(name)
+    if err != nil {
+      return nil, err
+    }
+    skip[density] = true
+  }
+  return skip, nil
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+  "fmt"
+  "log"
+  "os"
+  "path/filepath"
+  "sort"
+  "text/tabwriter"
+  "github.com/spf13/cobra"
+)
+
+var auditTop int
+
+var auditCmd = &cobra.Command{
+  Use: "audit [res folders]",
+  Short: "Report how many bytes the drawables take per density folder.",
+  Long: `Report how many bytes the drawables take per density folder.
+
+Lists the total size of every density folder, the --top largest files and
+what a device of each density downloads from an App Bundle, which only ships
+the closest density of each drawable. --output json prints the same for
+scripts.
+
+When andy.toml has a [budget], the files over it are listed too and the
+command exits nonzero, like andy budget. --baseline ignores the ones already
+over when it was written, so only new ones fail.
+
+  andy audit
+  andy audit app/src/main/res --top 20
+  andy audit --output json
+  andy audit --baseline andy-baseline.json`,
+  Run: func(cmd *cobra.Command, args []string) {
+    if len(args) > 0 {
+      resDirs = args
+    }
+    if err := checkReportFormat(cmd); err != nil { log.Fatal(err) }
+    resFolders, err := guessResFolders()
+    if err != nil { log.Fatal(err) }
+    files, err := scanResFiles(resFolders)
+    if err != nil { log.Fatal(err) }
+    if len(files) == 0 {
+      log.Fatal("no drawables found in the density folders.")
+    }
+    findings, err := auditFindings(files)
+    if err != nil { log.Fatal(err) }
+    if reportFormat == "json" {
+      report := auditReport(files)
+      report.Findings = findings
+      if err := printJSON(report); err != nil { log.Fatal(err) }
+    } else {
+      printAudit(files)
+      if len(findings) > 0 {
+        fmt.Println(red("over budget"))
+      }
+      for _, finding := range findings {
+        fmt.Printf("  %s %s\n", red("fail"), findingText(finding.Path, finding))
+      }
+    }
+    if len(findings) > 0 {
+      os.Exit(1)
+    }
+  },
+}
+
+func init() {
+  auditCmd.Flags().IntVar(&auditTop, "top", 10, "number of largest files to list")
+  addReportFlags(auditCmd, "table", "json")
+  addBaselineFlags(auditCmd)
+}
+
+// auditFindings are the files over the [budget] of the config, if there is
+// one, that the --baseline doesn't know about.
+func auditFindings(files []resFile) ([]Finding, error) {
+  limits, err := config.Budget.limits()
+  if err != nil {
+    return nil, err
+  }
+  findings := []Finding{}
+  if !limits.empty() {
+    findings, _ = checkBudget(files, limits)
+  }
+  findings, err = applyBaseline(findings)
+  if findings == nil {
+    findings = []Finding{}
+  }
+  return findings, err
+}
+
+type auditJSON struct {
+  Folders []folderJSON `json:"folders"`
+  Total folderJSON `json:"total"`
+  Largest []fileJSON `json:"largest"`
+  Devices []deviceJSON `json:"devices"`
+  Findings []Finding `json:"findings"`
+}
+
+type folderJSON struct {
+  Path string `json:"path,omitempty"`
+  Files int `json:"files"`
+  Bytes int64 `json:"bytes"`
+}
+
+type deviceJSON struct {
+  Density string `json:"density"`
+  Bytes int64 `json:"bytes"`
+}
+
+func auditReport(files []resFile) auditJSON {
+  report := auditJSON{Total: folderJSON{Files: len(files)}}
+  folders := map[string]*folderJSON{}
+  var paths []string
+  for _, file := range files {
+    folder := filepath.Dir(file.Path)
+    if folders[folder] == nil {
+      folders[folder] = &folderJSON{Path: filepath.ToSlash(folder)}
+      paths = append(paths, folder)
+    }
+    folders[folder].Files++
+    folders[folder].Bytes += file.Size
+    report.Total.Bytes += file.Size
+  }
+  sort.Strings(paths)
+  for _, path := range paths {
+    report.Folders = append(report.Folders, *folders[path])
+  }
+  for _, file := range largestFiles(files) {
+    report.Largest = append(report.Largest, file.JSON())
+  }
+  for _, density := range ascendingDensityList {
+    report.Devices = append(report.Devices, deviceJSON{Density: densityToCanonical[density], Bytes: deviceDownload(files, density)})
+  }
+  return report
+}
+
+// largestFiles is the --top largest of files, largest first.
+func largestFiles(files []resFile) []resFile {
+  largest := append([]resFile(nil), files...)
+  sort.SliceStable(largest, func(i, j int) bool { return largest[i].Size > largest[j].Size })
+  if len(largest) > auditTop {
+    largest = largest[:auditTop]
+  }
+  return largest
+}
+
+func printAudit(files []resFile) {
+  writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+  fmt.Println(green("folders"))
+  folderSizes := map[string]int64{}
+  folderCounts := map[string]int{}
+  var total int64
+  for _, file := range files {
+    folder := filepath.Dir(file.Path)
+    folderSizes[folder] += file.Size
+    folderCounts[folder]++
+    total += file.Size
+  }
+  var folders []string
+  for folder := range folderSizes {
+    folders = append(folders, folder)
+  }
+  sort.Strings(folders)
+  for _, folder := range folders {
+    fmt.Fprintf(writer, "  %s\t%d files\t%s\t\n", folder, folderCounts[folder], formatBytes(folderSizes[folder]))
+  }
+  fmt.Fprintf(writer, "  %s\t%d files\t%s\t\n", "total", len(files), formatBytes(total))
+  writer.Flush()
+
+  fmt.Println(green("largest"))
+  for _, file := range largestFiles(files) {
+    dimens := ""
+    if size, err := file.Dimens(); err == nil {
+      dimens = fmt.Sprintf("%dx%d", size.X, size.Y)
+    }
+    fmt.Fprintf(writer, "  %s\t%s\t%s\t\n", file.Path, formatBytes(file.Size), dimens)
+  }
+  writer.Flush()
+
+  fmt.Println(green("per device"))
+  for _, density := range ascendingDensityList {
+    fmt.Fprintf(writer, "  %s\t%s\t\n", densityToCanonical[density], formatBytes(deviceDownload(files, density)))
+  }
+  writer.Flush()
+}
+
+// deviceDownload estimates the bytes a device at density downloads: for each
+// drawable only its closest density, preferring higher ones to scale down
+// from, like App Bundle density splits.
+func deviceDownload(files []resFile, density dpi) (total int64) {
+  byResource := map[string][]resFile{}
+  for _, file := range files {
+    key := file.ResFolder + "|" + file.Resource()
+    byResource[key] = append(byResource[key], file)
+  }
+  for _, group := range byResource {
+    var best *resFile
+    for i := range group {
+      file := &group[i]
+      switch {
+      case best == nil:
+        best = file
+      case file.Density >= density && (best.Density < density || file.Density < best.Density):
+        best = file
+      case best.Density < density && file.Density > best.Density:
+        best = file
+      }
+    }
+    total += best.Size
+  }
+  return
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+  "fmt"
+  "os"
+  "path/filepath"
+  "strconv"
+  "strings"
+  "sync"
+  "time"
+)
+
+// andy keeps its own state in .andy in the working directory.
+const stateDir = ".andy"
+
+var journalDir = filepath.Join(stateDir, "journal")
+
+// runID names this run's backup folder and journal, in the order runs
+// happened.
+var (
+  runOnce sync.Once
+  runStamp string
+)
+
+func runID() string {
+  runOnce.Do(func() {
+    runStamp = time.Now().Format("20060102-150405.000")
+  })
+  return runStamp
+}
+
+// backupFile saves the contents of path, about to be replaced, under
+// .andy/backup/<timestamp>/ at the same path relative to the working
+// directory, and returns where it went.
+func backupFile(path string, data []byte) (string, error) {
+  rel := filepath.FromSlash(projectPath(path))
+  if rel == ".." || strings.HasPrefix(rel, ".." + string(filepath.Separator)) {
+    // outside the project, keep its whole path
+    rel = strings.TrimPrefix(tryGetAbsPath(path), filepath.VolumeName(tryGetAbsPath(path)))
+  }
+  target := filepath.Join(stateDir, "backup", runID(), rel)
+  if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+    return "", err
+  }
+  return target, os.WriteFile(target, data, 0644)
+}
+
+// JournalEntry is a file a run changed: created, or replaced with its old
+// contents saved at Backup, if they were.
+type JournalEntry struct {
+  Path string `toml:"path"`
+  Created bool `toml:"created,omitempty"`
+  Backup string `toml:"backup,omitempty"`
+}
+
+type Journal struct {
+  Files []JournalEntry `toml:"file"`
+}
+
+var journalMutex sync.Mutex
+
+// journalFile appends a changed file to this run's journal for andy undo.
+// Entries are appended as they happen so an interrupted run can be undone
+// too.
+func journalFile(entry JournalEntry) error {
+  journalMutex.Lock()
+  defer journalMutex.Unlock()
+  if err := os.MkdirAll(journalDir, 0755); err != nil {
+    return err
+  }
+  file, err := os.OpenFile(filepath.Join(journalDir, runID() + ".toml"), os.O_APPEND | os.O_CREATE | os.O_WRONLY, 0644)
+  if err != nil {
+    return err
+  }
+  block := fmt.Sprintf("[[file]]\npath = %s\n", strconv.Quote(projectPath(entry.Path)))
+  if entry.Created {
+    block += "created = true\n"
+  }
+  if entry.Backup != "" {
+    block += fmt.Sprintf("backup = %s\n", strconv.Quote(filepath.ToSlash(entry.Backup)))
+  }
+  if _, err := file.WriteString(block + "\n"); err != nil {
+    file.Close()
+    return err
+  }
+  return file.Close()
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+  "fmt"
+  "image"
+  "image/color"
+  "log"
+  "github.com/spf13/cobra"
+)
+
+const (
+  bannerWidthDp = 320
+  bannerHeightDp = 180
+)
+
+var (
+  bannerName string
+  bannerSafeMargin float64
+  bannerSourceSets []string
+)
+
+var bannerCmd = &cobra.Command{
+  Use: "banner [master]",
+  Short: "Generate the 320x180dp Android TV banner for every density from one master.",
+  Run: func(cmd *cobra.Command, args []string) {
+    if len(args) != 1 {
+      log.Fatal("need one master image.")
+    }
+    if err := checkOutputOptions(); err != nil {
+      log.Fatal(err)
+    }
+    if !cmd.Flags().Changed("source-set") {
+      bannerSourceSets = config.SourceSets
+    }
+
+    master, _, err := decodeImageFile(args[0])
+    if err != nil { log.Fatal(err) }
+    fmt.Printf("%s %s\n", green("from"), args[0])
+    master = fitBannerAspect(master)
+    checkBannerSafeArea(master, bannerSafeMargin)
+
+    resFolder, err := defaultResFolder()
+    if err != nil { log.Fatal(err) }
+    for _, target := range targetResFolders(resFolder, bannerSourceSets) {
+      if err := writeDensitySet(target, "drawable", bannerName + ".png", master, bannerWidthDp, bannerHeightDp); err != nil {
+        log.Fatal(err)
+      }
+    }
+  },
+}
+
+func init() {
+  addOutputFlags(bannerCmd)
+  bannerCmd.Flags().StringVar(&bannerName, "name", "banner", "resource name of the banner")
+  bannerCmd.Flags().Float64Var(&bannerSafeMargin, "safe-margin", 16, "margin in dp that logos and text should stay clear of")
+  bannerCmd.Flags().StringSliceVarP(&bannerSourceSets, "source-set", "s", nil, "source sets to write generated assets into (e.g. main,paid or all)")
+}
+
+// fitBannerAspect crops master to 16:9 so it isn't stretched, warning if it
+// had to.
+func fitBannerAspect(master image.Image) image.Image {
+  cropped, changed := cropToAspect(master, bannerWidthDp, bannerHeightDp)
+  if changed {
+    width, height := getDimens(&master)
+    croppedWidth, croppedHeight := getDimens(&cropped)
+    fmt.Printf("  %s master is %dx%d, cropping to %dx%d for a 16:9 banner\n", yellow("warn"), width, height, croppedWidth, croppedHeight)
+  }
+  return cropped
+}
+
+// checkBannerSafeArea warns when anything that differs from the banner's
+// background (taken from its top-left pixel) reaches into the safe margin.
+func checkBannerSafeArea(master image.Image, marginDp float64) {
+  src := toNRGBA(master)
+  width, height := src.Rect.Dx(), src.Rect.Dy()
+  background := src.NRGBAAt(0, 0)
+  content := image.Rectangle{}
+  for y := 0; y < height; y++ {
+    for x := 0; x < width; x++ {
+      if !similarColor(src.NRGBAAt(x, y), background) {
+        content = content.Union(image.Rect(x, y, x+1, y+1))
+      }
+    }
+  }
+  if content.Empty() {
+    return
+  }
+
+  pxPerDp := float64(width) / bannerWidthDp
+  margin := int(marginDp * pxPerDp)
+  safe := image.Rect(margin, margin, width-margin, height-margin)
+  if !content.In(safe) {
+    toDp := func(px int) float64 { return float64(px) / pxPerDp }
+    fmt.Printf("  %s content spans %.0f,%.0f-%.0f,%.0fdp, outside the %.0fdp safe margin\n", yellow("warn"),
+      toDp(content.Min.X), toDp(content.Min.Y), toDp(content.Max.X), toDp(content.Max.Y), marginDp)
+  }
+}
+
+func similarColor(a color.NRGBA, b color.NRGBA) bool {
+  diff := func(x, y uint8) int {
+    if x > y {
+      return int(x - y)
+    }
+    return int(y - x)
+  }
+  const tolerance = 16
+  return diff(a.R, b.R) <= tolerance && diff(a.G, b.G) <= tolerance && diff(a.B, b.B) <= tolerance && diff(a.A, b.A) <= tolerance
+}
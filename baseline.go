@@ -0,0 +1,85 @@
+package main
+
+import (
+  "encoding/json"
+  "fmt"
+  "os"
+  "github.com/spf13/cobra"
+)
+
+var (
+  baselinePath string
+  updateBaseline bool
+)
+
+// addBaselineFlags adds --baseline to a command that fails on findings.
+func addBaselineFlags(cmd *cobra.Command) {
+  cmd.Flags().StringVar(&baselinePath, "baseline", "", "json file of known findings to ignore, written with every current finding if it doesn't exist")
+  cmd.Flags().BoolVar(&updateBaseline, "update-baseline", false, "rewrite the --baseline file with every current finding")
+}
+
+// Baseline is the findings a project has accepted for now, so only new ones
+// fail. A finding matches when its check and path do, so it stays matched
+// while the details in its message change. Findings about no file in
+// particular, like the total budget, are never baselined: with nothing but
+// their check to match on, they'd stay ignored however much worse they got.
+type Baseline struct {
+  Findings []Finding `json:"findings"`
+}
+
+// applyBaseline drops the findings in the --baseline file. If there's no
+// file yet, or --update-baseline is passed, it's written with all findings
+// instead and only the ones without a path are left.
+func applyBaseline(findings []Finding) ([]Finding, error) {
+  if baselinePath == "" {
+    if updateBaseline {
+      return nil, fmt.Errorf("--update-baseline needs --baseline")
+    }
+    return findings, nil
+  }
+  data, err := os.ReadFile(baselinePath)
+  if os.IsNotExist(err) || updateBaseline {
+    baseline := Baseline{Findings: []Finding{}}
+    var left []Finding
+    for _, finding := range findings {
+      if finding.Path == "" {
+        left = append(left, finding)
+        continue
+      }
+      finding.Path = projectPath(finding.Path)
+      baseline.Findings = append(baseline.Findings, finding)
+    }
+    data, err := json.MarshalIndent(baseline, "", "  ")
+    if err != nil {
+      return nil, err
+    }
+    if err := writeAtomic(baselinePath, append(data, '\n')); err != nil {
+      return nil, err
+    }
+    printf("%s wrote %d findings to %s\n", green("baseline"), len(baseline.Findings), baselinePath)
+    return left, nil
+  }
+  if err != nil {
+    return nil, err
+  }
+  var baseline Baseline
+  if err := json.Unmarshal(data, &baseline); err != nil {
+    return nil, fmt.Errorf("%s: %v", baselinePath, err)
+  }
+  known := map[[2]string]bool{}
+  for _, finding := range baseline.Findings {
+    if finding.Path != "" {
+      known[[2]string{finding.Check, projectPath(finding.Path)}] = true
+    }
+  }
+  var left []Finding
+  for _, finding := range findings {
+    if finding.Path == "" || !known[[2]string{finding.Check, projectPath(finding.Path)}] {
+      left = append(left, finding)
+    }
+  }
+  if ignored := len(findings) - len(left); ignored > 0 {
+    printf("%s %d known findings in %s ignored\n", green("baseline"), ignored, baselinePath)
+  }
+  return left, nil
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+  "fmt"
+  "io/fs"
+  "os"
+  "path/filepath"
+  "regexp"
+  "sort"
+  "strings"
+)
+
+// expandAssetArgs expands glob patterns, for shells that don't, and replaces
+// folder arguments with the images to process in them: every image of a
+// density folder, or the source (highest density) of every drawable in the
+// res folders under it.
+func expandAssetArgs(args []string) (paths []string, err error) {
+  var expanded []string
+  for _, arg := range args {
+    if !strings.ContainsAny(arg, "*?[") || pathExists(arg) {
+      expanded = append(expanded, arg)
+      continue
+    }
+    matches, err := globFiles(arg)
+    if err != nil {
+      return nil, err
+    }
+    if len(matches) == 0 {
+      return nil, fmt.Errorf("nothing matches %s", arg)
+    }
+    expanded = append(expanded, matches...)
+  }
+
+  for _, arg := range expanded {
+    if !dirExists(arg) {
+      paths = append(paths, arg)
+      continue
+    }
+    found, err := dirAssets(arg)
+    if err != nil {
+      return nil, err
+    }
+    if len(found) == 0 {
+      return nil, fmt.Errorf("no images found in %s", arg)
+    }
+    paths = append(paths, found...)
+  }
+  return
+}
+
+// globFiles is filepath.Glob with ** matching any number of folders.
+func globFiles(pattern string) ([]string, error) {
+  pattern = filepath.ToSlash(filepath.Clean(pattern))
+  if !strings.Contains(pattern, "**") {
+    return filepath.Glob(pattern)
+  }
+
+  // walk from the folder before the first wildcard, matching what's under it
+  root := "."
+  if static := pattern[:strings.IndexAny(pattern, "*?[")]; strings.Contains(static, "/") {
+    root = static[:strings.LastIndex(static, "/")]
+  }
+  expr, err := globRegex(pattern)
+  if err != nil {
+    return nil, err
+  }
+  re, err := regexp.Compile("^" + expr + "$")
+  if err != nil {
+    return nil, fmt.Errorf("bad pattern %s", pattern)
+  }
+
+  var matches []string
+  err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+    if err != nil {
+      return err
+    }
+    if entry.IsDir() && path != root && ignored(path, true) {
+      return filepath.SkipDir
+    }
+    if !entry.IsDir() && re.MatchString(filepath.ToSlash(path)) && !ignored(path, false) {
+      matches = append(matches, path)
+    }
+    return nil
+  })
+  return matches, err
+}
+
+// globRegex translates a slash separated glob, where ** matches any number
+// of folders, into a regular expression.
+func globRegex(pattern string) (string, error) {
+  expr := ""
+  for i := 0; i < len(pattern); i++ {
+    switch {
+    case strings.HasPrefix(pattern[i:], "**/"):
+      expr += "(.*/)?"
+      i += 2
+    case strings.HasPrefix(pattern[i:], "**"):
+      expr += ".*"
+      i++
+    case pattern[i] == '*':
+      expr += "[^/]*"
+    case pattern[i] == '?':
+      expr += "[^/]"
+    case pattern[i] == '[':
+      end := strings.IndexByte(pattern[i:], ']')
+      if end < 0 {
+        return "", fmt.Errorf("bad pattern %s", pattern)
+      }
+      class := pattern[i:i+end+1]
+      if strings.HasPrefix(class, "[!") {
+        // gitignore style negation
+        class = "[^" + class[2:]
+      }
+      expr += class
+      i += end
+    default:
+      expr += regexp.QuoteMeta(pattern[i:i+1])
+    }
+  }
+  return expr, nil
+}
+
+func dirAssets(dir string) (paths []string, err error) {
+  if _, ok := folderToDensity[filepath.Base(filepath.Clean(dir))]; ok {
+    return folderImages(dir)
+  }
+
+  resFolders := map[string]bool{}
+  err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+    if err != nil {
+      return err
+    }
+    if entry.IsDir() && path != dir && ignored(path, true) {
+      return filepath.SkipDir
+    }
+    if _, ok := folderToDensity[entry.Name()]; ok && entry.IsDir() {
+      resFolders[filepath.Dir(path)] = true
+      return filepath.SkipDir
+    }
+    return nil
+  })
+  if err != nil {
+    return
+  }
+  for resFolder := range resFolders {
+    for path := range sourceDrawables(resFolder) {
+      paths = append(paths, path)
+    }
+  }
+  sort.Strings(paths)
+  return
+}
+
+// folderImages lists the images directly in dir, leaving out nine-patches
+// and anything .andyignore excludes.
+func folderImages(dir string) (paths []string, err error) {
+  entries, err := os.ReadDir(dir)
+  if err != nil {
+    return nil, err
+  }
+  for _, entry := range entries {
+    ext := filepath.Ext(entry.Name())
+    path := filepath.Join(dir, entry.Name())
+    if !entry.IsDir() && isBitmapExt(ext) && !strings.HasSuffix(strings.TrimSuffix(entry.Name(), ext), ".9") && !ignored(path, false) {
+      paths = append(paths, path)
+    }
+  }
+  return paths, nil
+}
+
+// printSummary prints what was written for each asset of a batch and why
+// any failed, exiting nonzero if one did.
+func printSummary(assets []string, failures []error) {
+  if reportSummary(assets, failures) > 0 {
+    os.Exit(1)
+  }
+}
+
+// reportSummary is printSummary for callers with cleanup to do before
+// exiting. It returns how many assets failed.
+func reportSummary(assets []string, failures []error) (failed int) {
+  for _, err := range failures {
+    if err != nil {
+      failed++
+    }
+  }
+  fmt.Printf("%s %d succeeded, %d failed\n", green("summary"), len(assets) - failed, failed)
+  for i, asset := range assets {
+    if failures[i] != nil {
+      fmt.Printf("  %s %s: %v\n", red("fail"), asset, failures[i])
+      continue
+    }
+    counts := outputCounts[asset]
+    if counts == nil {
+      counts = &fileCounts{}
+    }
+    fmt.Printf("  %s %s: %d written", green("->"), asset, counts.Written)
+    if counts.Skipped > 0 {
+      fmt.Printf(", %d skipped", counts.Skipped)
+    }
+    fmt.Println()
+  }
+  return failed
+}
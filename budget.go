@@ -0,0 +1,152 @@
+package main
+
+import (
+  "fmt"
+  "log"
+  "os"
+  "sort"
+  "strconv"
+  "strings"
+  "github.com/spf13/cobra"
+)
+
+// BudgetConfig caps the bytes drawables may take, as sizes like 200KB.
+type BudgetConfig struct {
+  // Total is the most all the images of the res folders may add up to
+  Total string `toml:"total" yaml:"total"`
+  // File is the most any one file may take
+  File string `toml:"file" yaml:"file"`
+  // Densities is the most any one file may take in a bucket, e.g. xxxhdpi
+  Densities map[string]string `toml:"densities" yaml:"densities"`
+}
+
+// budgetLimits is a BudgetConfig with its sizes parsed, 0 for no limit.
+type budgetLimits struct {
+  Total int64
+  File int64
+  Densities map[dpi]int64
+}
+
+func (budget BudgetConfig) limits() (limits budgetLimits, err error) {
+  if limits.Total, err = parseBytes(budget.Total); err != nil {
+    return limits, fmt.Errorf("budget total: %v", err)
+  }
+  if limits.File, err = parseBytes(budget.File); err != nil {
+    return limits, fmt.Errorf("budget file: %v", err)
+  }
+  limits.Densities = map[dpi]int64{}
+  for bucket, size := range budget.Densities {
+    density, ok := canonicalToDensity(bucket)
+    if !ok {
+      return limits, fmt.Errorf("budget densities: unknown density %q", bucket)
+    }
+    if limits.Densities[density], err = parseBytes(size); err != nil {
+      return limits, fmt.Errorf("budget densities %s: %v", bucket, err)
+    }
+  }
+  return limits, nil
+}
+
+func (limits budgetLimits) empty() bool {
+  return limits.Total == 0 && limits.File == 0 && len(limits.Densities) == 0
+}
+
+func canonicalToDensity(name string) (dpi, bool) {
+  for density, canonical := range densityToCanonical {
+    if canonical == name {
+      return density, true
+    }
+  }
+  return 0, false
+}
+
+var byteUnits = map[string]int64{"": 1, "B": 1, "KB": 1 << 10, "MB": 1 << 20, "GB": 1 << 30}
+
+// parseBytes reads a size like 200KB or 1.5MB, the way formatBytes writes
+// them. An empty size is 0.
+func parseBytes(size string) (int64, error) {
+  upper := strings.ToUpper(strings.TrimSpace(size))
+  if upper == "" {
+    return 0, nil
+  }
+  number := strings.TrimRight(upper, "KMGB")
+  unit, ok := byteUnits[upper[len(number):]]
+  value, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+  if !ok || err != nil || value < 0 {
+    return 0, fmt.Errorf("can't read size %q, ex: 200KB or 8MB", size)
+  }
+  return int64(value * float64(unit)), nil
+}
+
+var budgetCmd = &cobra.Command{
+  Use: "budget [res folders]",
+  Short: "Check drawables against the size budgets in the config.",
+  Long: `Check drawables against the size budgets in the config.
+
+The [budget] table of andy.toml caps the bytes drawables may take: total for
+all the images of the res folders together, file for any single file, and
+[budget.densities] for any single file in a bucket. Every file over its
+budget is listed and the command exits nonzero, so it can run in CI.
+--baseline ignores files already over budget when it was written, and
+--output prints json, sarif or github annotations, like andy check.
+
+  andy budget
+  andy budget --baseline andy-baseline.json`,
+  Run: func(cmd *cobra.Command, args []string) {
+    if len(args) > 0 {
+      resDirs = args
+    }
+    if err := checkReportFormat(cmd); err != nil { log.Fatal(err) }
+    limits, err := config.Budget.limits()
+    if err != nil { log.Fatal(err) }
+    if limits.empty() {
+      log.Fatal("no budgets set, add a [budget] table to andy.toml.")
+    }
+    resFolders, err := guessResFolders()
+    if err != nil { log.Fatal(err) }
+    files, err := scanResFiles(resFolders)
+    if err != nil { log.Fatal(err) }
+
+    findings, total := checkBudget(files, limits)
+    findings, err = applyBaseline(findings)
+    if err != nil { log.Fatal(err) }
+    if reportFormat != "table" {
+      reportFindings(findings, len(files), "files")
+      return
+    }
+    for _, finding := range findings {
+      fmt.Printf("  %s %s\n", red("fail"), findingText(finding.Path, finding))
+    }
+    if len(findings) > 0 {
+      fmt.Printf("%s %d over budget\n", red("budget"), len(findings))
+      os.Exit(1)
+    }
+    fmt.Printf("%s %d files, %s in total\n", green("budget"), len(files), formatBytes(total))
+  },
+}
+
+func init() {
+  addBaselineFlags(budgetCmd)
+  addReportFlags(budgetCmd, "table", "json", "sarif", "github")
+}
+
+// checkBudget finds the files over their budget, largest first, and the
+// total when that's over too.
+func checkBudget(files []resFile, limits budgetLimits) (findings []Finding, total int64) {
+  sorted := append([]resFile(nil), files...)
+  sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Size > sorted[j].Size })
+  for _, file := range sorted {
+    total += file.Size
+    limit, bucket := limits.File, ""
+    if densityLimit, ok := limits.Densities[file.Density]; ok {
+      limit, bucket = densityLimit, densityToCanonical[file.Density] + " "
+    }
+    if limit > 0 && file.Size > limit {
+      findings = append(findings, Finding{Check: "budget", Path: file.Path, Message: fmt.Sprintf("is %s, over the %s %sbudget", formatBytes(file.Size), formatBytes(limit), bucket)})
+    }
+  }
+  if limits.Total > 0 && total > limits.Total {
+    findings = append(findings, Finding{Check: "budget", Message: fmt.Sprintf("drawables take %s in total, over the %s budget", formatBytes(total), formatBytes(limits.Total))})
+  }
+  return
+}
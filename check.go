@@ -0,0 +1,286 @@
+package main
+
+import (
+  "fmt"
+  "image"
+  "log"
+  "math"
+  "os"
+  "path/filepath"
+  "sort"
+  "strings"
+  "github.com/spf13/cobra"
+)
+
+var (
+  checkGrid float64
+  checkGenerated bool
+  checkMissing bool
+  checkFix bool
+)
+
+// Finding is a problem andy check found, with the file it's about.
+type Finding struct {
+  Check string `json:"check"`
+  Path string `json:"path"`
+  Message string `json:"message"`
+}
+
+var checkCmd = &cobra.Command{
+  Use: "check [images]",
+  Short: "Check source drawables for problems and exit nonzero if any are found.",
+  Long: `Check source drawables for problems and exit nonzero if any are found.
+
+Without arguments every res folder is checked, using the highest density of
+each drawable as its source. Sources whose dp size isn't on the --grid (4dp
+by default) are flagged, since they render as blurry half pixels in some
+buckets. Lower densities whose aspect ratio or size doesn't match the highest
+are flagged as well, they're usually left over from an older export, and so
+are variants that look upscaled from the density below. Files aapt would
+reject for their name (uppercase letters, dashes, a leading digit or an
+uppercase extension like .PNG) are flagged before the build fails on them,
+as are names used by both a drawable and a mipmap, drawables whose
+densities come in different formats, nine-patches with broken border
+markers, and densities that are byte for byte copies of a higher one.
+
+With --missing, drawables without every bucket below their highest density
+are flagged too (each qualified variant, like night, on its own). --fix
+renames badly named files in every density folder, regenerates copied
+densities from the highest copy, and fills the missing gaps by resizing the
+highest density.
+
+With --baseline, findings already in the baseline file are ignored so only
+new ones fail. The file is written with every current finding the first time
+(or with --update-baseline), the same way Android lint baselines work.
+
+--output json prints the findings for scripts, sarif for code scanning and
+github as workflow annotations, so they show up inline on pull requests.
+
+With --generated, the assets recorded in andy.lock are checked instead:
+sources changed since they were generated and outputs that were edited by
+hand or deleted are flagged.
+
+  andy check
+  andy check res/drawable-xxhdpi/ic_hero.png --grid 8
+  andy check --missing --fix
+  andy check --baseline andy-baseline.json
+  andy check --generated`,
+  Run: func(cmd *cobra.Command, args []string) {
+    if err := checkReportFormat(cmd); err != nil { log.Fatal(err) }
+    if checkGenerated {
+      checkDrift()
+      return
+    }
+    if err := checkOutputOptions(); err != nil {
+      log.Fatal(err)
+    }
+    sources := map[string]dpi{}
+    var resFolders []string
+    for _, path := range args {
+      density, err := imageDensity(path, "")
+      if err != nil { log.Fatal(err) }
+      sources[path] = density
+    }
+    if len(args) == 0 {
+      var err error
+      resFolders, err = guessResFolders()
+      if err != nil { log.Fatal(err) }
+      if checkFix {
+        if err := fixNames(resFolders); err != nil { log.Fatal(err) }
+      }
+      for _, resFolder := range resFolders {
+        for path, density := range sourceDrawables(resFolder) {
+          sources[path] = density
+        }
+      }
+    }
+
+    var findings []Finding
+    var paths []string
+    for path := range sources {
+      paths = append(paths, path)
+    }
+    sort.Strings(paths)
+    for _, path := range paths {
+      problems, err := checkSource(path, sources[path])
+      if err != nil { log.Fatal(err) }
+      for _, problem := range problems {
+        findings = append(findings, Finding{Check: "grid", Path: path, Message: problem})
+      }
+    }
+    if len(args) == 0 {
+      copies, err := identicalCopies(resFolders)
+      if err != nil { log.Fatal(err) }
+      if checkFix {
+        if err := fillMissing(copies); err != nil { log.Fatal(err) }
+        copies = nil
+      }
+      copied := map[string]bool{}
+      for _, copy := range copies {
+        findings = append(findings, copyFinding(copy))
+        copied[copy.Path()] = true
+      }
+      mismatched, err := checkVariants(resFolders)
+      if err != nil { log.Fatal(err) }
+      for _, finding := range mismatched {
+        // a copy's size is off too, once is enough
+        if !copied[finding.Path] {
+          findings = append(findings, finding)
+        }
+      }
+      upscaled, err := checkUpscaled(resFolders)
+      if err != nil { log.Fatal(err) }
+      findings = append(findings, upscaled...)
+      misnamed, err := checkNames(resFolders)
+      if err != nil { log.Fatal(err) }
+      findings = append(findings, misnamed...)
+      collisions, err := checkCollisions(resFolders)
+      if err != nil { log.Fatal(err) }
+      findings = append(findings, collisions...)
+      ninePatches, err := checkNinePatches(resFolders)
+      if err != nil { log.Fatal(err) }
+      findings = append(findings, ninePatches...)
+    }
+    if checkMissing && len(args) == 0 {
+      missing, err := missingDensities(resFolders)
+      if err != nil { log.Fatal(err) }
+      if checkFix {
+        if err := fillMissing(missing); err != nil { log.Fatal(err) }
+        missing = nil
+      }
+      for _, gap := range missing {
+        findings = append(findings, gap.Finding())
+      }
+    }
+
+    findings, err := applyBaseline(findings)
+    if err != nil { log.Fatal(err) }
+    reportFindings(findings, len(sources), "sources")
+  },
+}
+
+// reportFindings prints findings in the --output format, and exits nonzero
+// if there are any.
+func reportFindings(findings []Finding, checked int, what string) {
+  if reportFormat != "table" {
+    if err := printFindingsReport(checked, findings); err != nil { log.Fatal(err) }
+    if len(findings) > 0 {
+      os.Exit(1)
+    }
+    return
+  }
+  for _, finding := range findings {
+    fmt.Printf("  %s %s\n", yellow("warn"), findingText(finding.Path, finding))
+  }
+  if len(findings) > 0 {
+    fmt.Printf("%s %d issues in %d %s\n", yellow("check"), len(findings), checked, what)
+    os.Exit(1)
+  }
+  fmt.Printf("%s %d %s ok\n", green("check"), checked, what)
+}
+
+func init() {
+  checkCmd.Flags().Float64Var(&checkGrid, "grid", 4, "dp grid source sizes should land on, 0 to skip")
+  addOutputFlags(checkCmd)
+  checkCmd.Flags().BoolVar(&checkMissing, "missing", false, "also flag drawables missing buckets below their highest density")
+  checkCmd.Flags().BoolVar(&checkFix, "fix", false, "rename badly named files, regenerate copied densities and fill missing buckets, instead of flagging them")
+  checkCmd.Flags().BoolVar(&checkGenerated, "generated", false, "check generated assets still match andy.lock instead")
+  addBaselineFlags(checkCmd)
+  addReportFlags(checkCmd, "table", "json", "sarif", "github")
+}
+
+// checkDrift exits nonzero when the files in andy.lock no longer match it.
+func checkDrift() {
+  if !fileExists(lockFile) {
+    log.Fatalf("no %s here, generate assets with andy dpi first", lockFile)
+  }
+  if err := loadLock(); err != nil { log.Fatal(err) }
+  drift := lockDrift()
+  var paths []string
+  for path := range drift {
+    paths = append(paths, path)
+  }
+  sort.Strings(paths)
+  var findings []Finding
+  for _, path := range paths {
+    findings = append(findings, Finding{Check: "generated", Path: path, Message: drift[path]})
+  }
+  reportFindings(findings, len(lockedAssets), "generated sources")
+}
+
+// sourceDrawables maps the highest density bitmap of each drawable in
+// resFolder to its density. Nine-patches are skipped, their border isn't
+// part of the size, and so is anything .andyignore excludes.
+func sourceDrawables(resFolder string) map[string]dpi {
+  sources := map[string]dpi{}
+  seen := map[string]bool{}
+  for _, folder := range densityPriorityList {
+    entries, err := os.ReadDir(filepath.Join(resFolder, folder))
+    if err != nil {
+      continue
+    }
+    for _, entry := range entries {
+      name := entry.Name()
+      ext := filepath.Ext(name)
+      resName := strings.TrimSuffix(name, ext)
+      path := filepath.Join(resFolder, folder, name)
+      if entry.IsDir() || !isBitmapExt(ext) || strings.HasSuffix(resName, ".9") || seen[resName] {
+        continue
+      }
+      seen[resName] = true
+      // an ignored source leaves its lower densities alone too
+      if !ignored(path, false) {
+        sources[path] = folderToDensity[folder]
+      }
+    }
+  }
+  return sources
+}
+
+func isBitmapExt(ext string) bool {
+  for _, formatExt := range formatExtensions {
+    if strings.EqualFold(ext, formatExt) {
+      return true
+    }
+  }
+  return strings.EqualFold(ext, ".jpeg")
+}
+
+// checkSource reports what's wrong with the source image at path.
+func checkSource(path string, density dpi) (problems []string, err error) {
+  file, err := os.Open(path)
+  if err != nil {
+    return
+  }
+  defer file.Close()
+  bounds, _, err := image.DecodeConfig(file)
+  if err != nil {
+    return nil, fmt.Errorf("%s: %v", path, err)
+  }
+  return gridProblems(bounds.Width, bounds.Height, density), nil
+}
+
+// gridProblems says if a source of width x height pixels at density is off
+// the --grid.
+func gridProblems(width int, height int, density dpi) (problems []string) {
+  widthDp, heightDp := float64(width) * MDPI / float64(density), float64(height) * MDPI / float64(density)
+  if checkGrid == 0 {
+    return
+  }
+  if !isWhole(widthDp) || !isWhole(heightDp) || !onGrid(widthDp, checkGrid) || !onGrid(heightDp, checkGrid) {
+    problem := fmt.Sprintf("is %sx%sdp, off the %gdp grid", formatSize(widthDp), formatSize(heightDp), checkGrid)
+    for _, bucket := range ascendingDensityList {
+      width, height := widthDp * float64(bucket) / MDPI, heightDp * float64(bucket) / MDPI
+      if !isWhole(width) || !isWhole(height) {
+        problem += fmt.Sprintf(" (%sx%spx at %s)", formatSize(width), formatSize(height), densityToCanonical[bucket])
+        break
+      }
+    }
+    problems = append(problems, problem)
+  }
+  return
+}
+
+func onGrid(dp float64, grid float64) bool {
+  return isWhole(dp / grid) && math.Round(dp / grid) > 0
+}
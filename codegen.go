@@ -0,0 +1,106 @@
+package main
+
+import (
+  "fmt"
+  "path/filepath"
+  "regexp"
+  "strconv"
+  "strings"
+)
+
+const kotlinDimens = `%s// Generated by andy from design values, don't edit.
+object %s {
+%s}
+`
+
+const javaDimens = `%s// Generated by andy from design values, don't edit.
+public final class %s {
+    private %s() {}
+
+%s}
+`
+
+var (
+  convertGen string
+  convertPackage string
+  convertClass string
+  convertGenDir string
+)
+
+// namedValue is a convert input with an optional constant name, as
+// value:name like andy dimens takes.
+type namedValue struct {
+  Name string
+  Input string
+  Value float64
+}
+
+// generateConstants writes a Kotlin object or Java class with a float
+// constant per input. Physical units and px are converted to dp, sp is kept.
+func generateConstants(inputs []string) error {
+  if convertGen != "kotlin" && convertGen != "java" {
+    return fmt.Errorf("unknown --gen language %q, expected kotlin or java", convertGen)
+  }
+  source, err := pxSource()
+  if err != nil {
+    return err
+  }
+  var values []namedValue
+  for _, input := range inputs {
+    value, err := parseNamedValue(input, source)
+    if err != nil {
+      return err
+    }
+    values = append(values, value)
+  }
+
+  header := ""
+  if convertPackage != "" {
+    header = "package " + convertPackage
+    if convertGen == "java" {
+      header += ";"
+    }
+    header += "\n\n"
+  }
+  constants := ""
+  for _, value := range values {
+    number := strconv.FormatFloat(value.Value, 'f', -1, 64) + "f"
+    if convertGen == "kotlin" {
+      constants += fmt.Sprintf("    const val %s = %s // %s\n", value.Name, number, value.Input)
+    } else {
+      constants += fmt.Sprintf("    public static final float %s = %s; // %s\n", value.Name, number, value.Input)
+    }
+  }
+
+  if convertGen == "kotlin" {
+    return writeFile(filepath.Join(convertGenDir, convertClass + ".kt"), []byte(fmt.Sprintf(kotlinDimens, header, convertClass, constants)))
+  }
+  return writeFile(filepath.Join(convertGenDir, convertClass + ".java"), []byte(fmt.Sprintf(javaDimens, header, convertClass, convertClass, constants)))
+}
+
+var constantNameRegex = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+func parseNamedValue(input string, source dpi) (namedValue, error) {
+  measurement, name := input, ""
+  if parts := strings.SplitN(input, ":", 2); len(parts) == 2 {
+    measurement, name = parts[0], parts[1]
+  }
+  value, unit, err := parseMeasurement(measurement)
+  if err != nil {
+    return namedValue{}, err
+  }
+  if unit != "sp" {
+    if value, err = convertDp(value, unit, source); err != nil {
+      return namedValue{}, err
+    }
+    value, unit = roundTo(value, 2), "dp"
+  }
+  if name == "" {
+    name = fmt.Sprintf("%s_%s", unit, strconv.FormatFloat(value, 'f', -1, 64))
+  }
+  name = strings.ToUpper(strings.Trim(constantNameRegex.ReplaceAllString(name, "_"), "_"))
+  if name == "" || (name[0] >= '0' && name[0] <= '9') {
+    return namedValue{}, fmt.Errorf("can't make a constant name from %q", input)
+  }
+  return namedValue{Name: name, Input: strings.TrimSpace(measurement), Value: value}, nil
+}
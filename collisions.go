@@ -0,0 +1,52 @@
+package main
+
+import (
+  "fmt"
+  "path/filepath"
+  "strings"
+)
+
+// checkCollisions flags names that resolve in surprising ways: the same name
+// as both a drawable and a mipmap, one density folder with the same name in
+// two formats, which aapt rejects as a duplicate resource, and densities of
+// a drawable in a different format than its highest one.
+func checkCollisions(resFolders []string) (findings []Finding, err error) {
+  for _, resFolder := range resFolders {
+    files, err := scanResFiles([]string{resFolder})
+    if err != nil {
+      return nil, err
+    }
+    types := map[string]map[string]resFile{}
+    for _, file := range files {
+      if types[file.Name] == nil {
+        types[file.Name] = map[string]resFile{}
+      }
+      if _, ok := types[file.Name][file.Type]; !ok {
+        types[file.Name][file.Type] = file
+      }
+    }
+    for _, file := range files {
+      if mipmap, ok := types[file.Name]["mipmap"]; ok && file.Type == "drawable" && types[file.Name]["drawable"] == file {
+        findings = append(findings, Finding{Check: "collisions", Path: file.Path, Message: fmt.Sprintf("has the same name as %s, use one or the other", mipmap.Path)})
+      }
+    }
+
+    groups, names := groupResources(files)
+    for _, name := range names {
+      group := groups[name]
+      reference := group[len(group) - 1]
+      seen := map[dpi]resFile{}
+      for _, file := range group {
+        if other, ok := seen[file.Density]; ok {
+          findings = append(findings, Finding{Check: "collisions", Path: file.Path, Message: fmt.Sprintf("is in the same folder as %s, aapt will fail on the duplicate resource", filepath.Base(other.Path))})
+          continue
+        }
+        seen[file.Density] = file
+        if ext, refExt := strings.ToLower(filepath.Ext(file.Path)), strings.ToLower(filepath.Ext(reference.Path)); ext != refExt && file.Density != reference.Density {
+          findings = append(findings, Finding{Check: "collisions", Path: file.Path, Message: fmt.Sprintf("is %s but %s is %s", ext, densityToCanonical[reference.Density], refExt)})
+        }
+      }
+    }
+  }
+  return
+}
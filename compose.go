@@ -0,0 +1,150 @@
+package main
+
+import (
+  "fmt"
+  "image"
+  "image/draw"
+  "log"
+  "math"
+  "strconv"
+  "strings"
+  "github.com/nfnt/resize"
+  "github.com/spf13/cobra"
+)
+
+var (
+  composeOut string
+  composeDensity string
+  composeOffsets []string
+  composeScales []string
+  composeSourceSets []string
+)
+
+var composeCmd = &cobra.Command{
+  Use: "compose [layers]",
+  Short: "Stack image layers bottom to top into a new drawable and generate all its densities.",
+  Long: `Stack image layers bottom to top into a new drawable and generate all its densities.
+
+The first layer sets the canvas size and every layer is centered on it. Move
+or scale individual layers with --offset and --scale, addressing them by
+their 1-based position:
+
+  andy compose background.png badge.png --out ic_combined --offset 2=8dp,-8dp --scale 2=0.5`,
+  Run: func(cmd *cobra.Command, args []string) {
+    if len(args) < 2 {
+      log.Fatal("need two or more layers.")
+    }
+    if composeOut == "" {
+      log.Fatal("need --out with the new drawable's name.")
+    }
+    if err := checkOutputOptions(); err != nil {
+      log.Fatal(err)
+    }
+    if !cmd.Flags().Changed("source-set") {
+      composeSourceSets = config.SourceSets
+    }
+    density := ascendingDensityList[len(ascendingDensityList)-1]
+    if composeDensity != "" {
+      var err error
+      density, err = parseDensity(composeDensity)
+      if err != nil { log.Fatal(err) }
+    }
+    offsets, err := parseLayerOptions(composeOffsets, len(args), parseDpOffset)
+    if err != nil { log.Fatal(err) }
+    scales, err := parseLayerOptions(composeScales, len(args), parseScale)
+    if err != nil { log.Fatal(err) }
+
+    var layers []image.Image
+    for _, arg := range args {
+      layer, _, err := decodeImageFile(arg)
+      if err != nil { log.Fatal(err) }
+      fmt.Printf("%s %s\n", green("from"), arg)
+      layers = append(layers, layer)
+    }
+    composed := stackLayers(layers, offsets, scales, density)
+
+    resFolder, err := defaultResFolder()
+    if err != nil { log.Fatal(err) }
+    drawableInfo := DrawableInfo{ResFolder: resFolder, Density: density, Filename: withFormatExtension(composeOut, "png"), Format: "png"}
+    for _, resFolder := range targetResFolders(drawableInfo.ResFolder, composeSourceSets) {
+      target := drawableInfo
+      target.ResFolder = resFolder
+      if err := variantToFolders(&target, &composed, ""); err != nil {
+        log.Fatal(err)
+      }
+    }
+  },
+}
+
+func init() {
+  // --out names the drawable here, so it's taken before addOutputFlags
+  composeCmd.Flags().StringVar(&composeOut, "out", "", "name of the composed drawable")
+  addOutputFlags(composeCmd)
+  composeCmd.Flags().StringVar(&composeDensity, "density", "", "density the layers were drawn at (default: the highest bucket)")
+  composeCmd.Flags().StringArrayVar(&composeOffsets, "offset", nil, "layer offset from center in dp, as <layer>=<x>dp,<y>dp")
+  composeCmd.Flags().StringArrayVar(&composeScales, "scale", nil, "layer scale, as <layer>=<factor>")
+  composeCmd.Flags().StringSliceVarP(&composeSourceSets, "source-set", "s", nil, "source sets to write generated assets into (e.g. main,paid or all)")
+}
+
+// stackLayers sizes the canvas to the first layer and draws every layer
+// centered on it, scaled and then moved by its dp offset.
+func stackLayers(layers []image.Image, offsets map[int]interface{}, scales map[int]interface{}, density dpi) image.Image {
+  width, height := getDimens(&layers[0])
+  canvas := image.NewNRGBA(image.Rect(0, 0, width, height))
+  for i, layer := range layers {
+    if scale, ok := scales[i].(float64); ok {
+      layerWidth, layerHeight := getDimens(&layer)
+      layer = resize.Resize(uint(math.Round(float64(layerWidth)*scale)), uint(math.Round(float64(layerHeight)*scale)), layer, resize.Lanczos3)
+    }
+    layerWidth, layerHeight := getDimens(&layer)
+    at := image.Pt((width-layerWidth)/2, (height-layerHeight)/2)
+    if offset, ok := offsets[i].([2]float64); ok {
+      at = at.Add(image.Pt(dpToPx(offset[0], density), dpToPx(offset[1], density)))
+    }
+    draw.Draw(canvas, image.Rect(0, 0, layerWidth, layerHeight).Add(at), layer, layer.Bounds().Min, draw.Over)
+  }
+  return canvas
+}
+
+// parseLayerOptions parses <layer>=<value> flags into values keyed by the
+// 0-based layer index.
+func parseLayerOptions(values []string, layerCount int, parse func(string) (interface{}, error)) (map[int]interface{}, error) {
+  options := map[int]interface{}{}
+  for _, value := range values {
+    parts := strings.SplitN(value, "=", 2)
+    layer, err := strconv.Atoi(parts[0])
+    if len(parts) != 2 || err != nil || layer < 1 || layer > layerCount {
+      return nil, fmt.Errorf("invalid layer option %q, expected <layer>=<value> with layer 1-%d", value, layerCount)
+    }
+    parsed, err := parse(parts[1])
+    if err != nil {
+      return nil, err
+    }
+    options[layer-1] = parsed
+  }
+  return options, nil
+}
+
+func parseDpOffset(s string) (interface{}, error) {
+  parts := strings.Split(s, ",")
+  if len(parts) != 2 {
+    return nil, fmt.Errorf("invalid offset %q, expected <x>dp,<y>dp", s)
+  }
+  var offset [2]float64
+  for i, part := range parts {
+    value, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(part), "dp"), 64)
+    if err != nil {
+      return nil, fmt.Errorf("invalid offset %q, expected <x>dp,<y>dp", s)
+    }
+    offset[i] = value
+  }
+  return offset, nil
+}
+
+func parseScale(s string) (interface{}, error) {
+  scale, err := strconv.ParseFloat(s, 64)
+  if err != nil || scale <= 0 {
+    return nil, fmt.Errorf("invalid scale %q", s)
+  }
+  return scale, nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+  "fmt"
+  "os"
+  "path/filepath"
+  "strings"
+  "github.com/BurntSushi/toml"
+  "github.com/spf13/cobra"
+  "gopkg.in/yaml.v3"
+)
+
+// configFiles are looked for in the current directory, in order.
+var configFiles = []string{"andy.toml", "andy.yaml", "andy.yml"}
+
+type Config struct {
+  ResDirs []string `toml:"res_dirs" yaml:"res_dirs"`
+  Densities map[string]float64 `toml:"densities" yaml:"densities"`
+  SourceSets []string `toml:"source_sets" yaml:"source_sets"`
+  NightTransform string `toml:"night_transform" yaml:"night_transform"`
+  StoreDir string `toml:"store_dir" yaml:"store_dir"`
+  Dimens map[string]string `toml:"dimens" yaml:"dimens"`
+  DimensScales map[string]float64 `toml:"dimens_scales" yaml:"dimens_scales"`
+  Format string `toml:"format" yaml:"format"`
+  Optimize OptimizeConfig `toml:"optimize" yaml:"optimize"`
+  Assets map[string]AssetConfig `toml:"assets" yaml:"assets"`
+  Budget BudgetConfig `toml:"budget" yaml:"budget"`
+  // Commands holds flag defaults per command, e.g. [commands.dpi] jobs = 4
+  Commands map[string]map[string]interface{} `toml:"commands" yaml:"commands"`
+  // Profiles are named sets of flags picked with --profile
+  Profiles map[string]map[string]interface{} `toml:"profiles" yaml:"profiles"`
+}
+
+// OptimizeConfig tunes how generated images are encoded.
+type OptimizeConfig struct {
+  Quality int `toml:"quality" yaml:"quality"`
+  PNGCompression string `toml:"png_compression" yaml:"png_compression"`
+}
+
+var (
+  configPath string
+  config Config
+)
+
+// findConfig is the first of configFiles that exists, or "".
+func findConfig() string {
+  for _, name := range configFiles {
+    if fileExists(name) {
+      return name
+    }
+  }
+  return ""
+}
+
+func loadConfig(path string) error {
+  switch strings.ToLower(filepath.Ext(path)) {
+  case ".yaml", ".yml":
+    data, err := os.ReadFile(path)
+    if err != nil {
+      return err
+    }
+    if err := yaml.Unmarshal(data, &config); err != nil {
+      return fmt.Errorf("%s: %v", path, err)
+    }
+  default:
+    if _, err := toml.DecodeFile(path, &config); err != nil {
+      return fmt.Errorf("%s: %v", path, err)
+    }
+  }
+
+  if len(config.Densities) > 0 {
+    if err := setDensityFolders(config.Densities); err != nil {
+      return fmt.Errorf("%s: %v", path, err)
+    }
+  }
+  if config.Format != "" {
+    if _, ok := formatExtensions[config.Format]; !ok {
+      return fmt.Errorf("%s: unknown format %q, expected png, webp or jpeg", path, config.Format)
+    }
+  }
+  if err := setEncodeOptions(config.Optimize.Quality, config.Optimize.PNGCompression); err != nil {
+    return fmt.Errorf("%s: %v", path, err)
+  }
+  configQuality = encodeQuality
+  if _, err := config.Budget.limits(); err != nil {
+    return fmt.Errorf("%s: %v", path, err)
+  }
+  return nil
+}
+
+// applyCommandDefaults sets the flags of cmd that weren't passed from the
+// --profile it's given, then from its table in [commands], so they work just
+// like typing them.
+func applyCommandDefaults(cmd *cobra.Command) error {
+  defaults := config.Commands[cmd.Name()]
+  if flag := cmd.Flags().Lookup("profile"); flag != nil {
+    name := flag.Value.String()
+    if value, ok := defaults["profile"]; ok && !flag.Changed {
+      name = fmt.Sprint(value)
+    }
+    if name != "" {
+      profile, ok := config.Profiles[name]
+      if !ok {
+        return fmt.Errorf("no profile %q in %s", name, configPath)
+      }
+      if err := setFlagDefaults(cmd, profile, "profiles." + name); err != nil {
+        return err
+      }
+    }
+  }
+  return setFlagDefaults(cmd, defaults, "commands." + cmd.Name())
+}
+
+func setFlagDefaults(cmd *cobra.Command, values map[string]interface{}, table string) error {
+  for name, value := range values {
+    flag := cmd.Flags().Lookup(name)
+    if flag == nil {
+      return fmt.Errorf("%s: %s: andy %s has no --%s flag", configPath, table, cmd.Name(), name)
+    }
+    if flag.Changed {
+      continue
+    }
+    values := []interface{}{value}
+    if list, ok := value.([]interface{}); ok {
+      values = list
+    }
+    for _, value := range values {
+      if err := cmd.Flags().Set(name, fmt.Sprint(value)); err != nil {
+        return fmt.Errorf("%s: %s.%s: %v", configPath, table, name, err)
+      }
+    }
+  }
+  return nil
+}
@@ -0,0 +1,500 @@
+package main
+
+import (
+  "bufio"
+  "encoding/csv"
+  "encoding/json"
+  "errors"
+  "fmt"
+  "log"
+  "math"
+  "os"
+  "strconv"
+  "strings"
+  "text/tabwriter"
+  "unicode"
+  "github.com/spf13/cobra"
+)
+
+// Android's named density buckets in dpi, including the ones andy doesn't
+// generate folders for.
+var namedDensities = map[string]float64{
+  "ldpi":    120,
+  "mdpi":    160,
+  "tvdpi":   213,
+  "hdpi":    240,
+  "xhdpi":   320,
+  "xxhdpi":  480,
+  "xxxhdpi": 640,
+}
+
+type densityTarget struct {
+  Label string
+  Density dpi
+}
+
+// The font scales Android's display settings offer.
+var commonFontScales = []float64{0.85, 1, 1.15, 1.3}
+
+// Android defines physical units at the nominal density of each bucket, so
+// one inch is always 160dp.
+const dpPerInch = 160
+
+var dpPerUnit = map[string]float64{
+  "in": dpPerInch,
+  "mm": dpPerInch / 25.4,
+  "pt": dpPerInch / 72.0,
+}
+
+var (
+  convertTargets []string
+  convertFontScales []float64
+  convertUnits []string
+  convertFile string
+  convertOutput string
+  convertDevice string
+  convertIOS bool
+)
+
+var convertCmd = &cobra.Command{
+  Use: "convert [units]",
+  Short: "Convert a density-independent unit to its corresponding pixel sizes per density.",
+  Long: `Convert a density-independent unit to its corresponding pixel sizes per density.
+
+Pixel values work the other way around: pass the density they were measured
+at with --at and andy prints the dp value and the pixels at every bucket.
+
+  andy convert 30dp --at 420dpi
+  andy convert 96px --at xxhdpi
+
+--device uses a known device's actual density instead, for either direction.
+
+  andy convert 48dp --device pixel8
+
+sp values are shown at each of Android's font scale settings, or the ones
+passed with --font-scale.
+
+  andy convert 14sp --font-scale 1.3
+
+Physical units (mm, in, pt) are accepted as input, and --to shows them
+alongside or instead of pixels.
+
+  andy convert 5mm
+  andy convert 48dp --to px,mm
+
+--ios adds the iOS point value and its @1x, @2x and @3x pixels, taking a
+point as one dp.
+
+  andy convert 48dp --ios
+
+Several values, given as arguments, with -f or piped in, are printed as one
+table, a row per value. --output csv or --output json print them for
+scripts instead.
+
+  andy convert -f dimens.txt --output csv
+  andy convert 48dp --output json
+
+--gen writes the values as Kotlin or Java float constants, named with
+value:name. px and physical units become dp, sp stays sp.
+
+  andy convert 16dp:spacing_large 14sp:text_body --gen kotlin --package com.foo.ui`,
+  Run: func(cmd *cobra.Command, args []string) {
+    inputs := args
+    if convertFile != "" || (len(args) == 0 && stdinIsPiped()) {
+      values, err := readConvertValues(convertFile)
+      if err != nil { log.Fatal(err) }
+      inputs = append(inputs, values...)
+    }
+    if len(inputs) == 0 {
+      log.Fatal("pass in one unit measurement, please. ex: 30dp")
+    }
+    for _, u := range convertUnits {
+      if _, ok := dpPerUnit[u]; !ok && u != "px" && u != "dp" {
+        log.Fatalf("unknown output unit %q, expected px, dp, mm, in or pt", u)
+      }
+    }
+
+    if convertGen != "" {
+      if err := generateConstants(inputs); err != nil { log.Fatal(err) }
+      return
+    }
+    if len(inputs) == 1 && !cmd.Flags().Changed("output") {
+      if err := convertOne(inputs[0]); err != nil { log.Fatal(err) }
+      return
+    }
+    if err := printConversions(inputs, convertOutput); err != nil { log.Fatal(err) }
+  },
+}
+
+// convertDp turns a measurement into dp. px values need the density they
+// were measured at.
+func convertDp(value float64, unit string, source dpi) (float64, error) {
+  if perUnit, ok := dpPerUnit[unit]; ok {
+    return value * perUnit, nil
+  }
+  if unit == "px" {
+    if source == 0 {
+      return 0, errors.New("pixel values need the density they're at, ex: 96px --at xxhdpi")
+    }
+    return value * MDPI / float64(source), nil
+  }
+  return value, nil
+}
+
+// pxSource is the density px inputs were measured at: the --device, or a
+// single --at.
+func pxSource() (dpi, error) {
+  if convertDevice != "" {
+    d, err := findDevice(convertDevice)
+    return d.Screen.Density, err
+  }
+  if len(convertTargets) != 1 {
+    return 0, nil
+  }
+  return parseDensity(convertTargets[0])
+}
+
+// convertDensities is what values are shown at: every bucket for px inputs,
+// otherwise the --at densities and --device, or every bucket.
+func convertDensities(hasPx bool) ([]densityTarget, error) {
+  if hasPx {
+    return parseDensityTargets(nil)
+  }
+  if convertDevice == "" {
+    return parseDensityTargets(convertTargets)
+  }
+  d, err := findDevice(convertDevice)
+  if err != nil {
+    return nil, err
+  }
+  var targets []densityTarget
+  if len(convertTargets) > 0 {
+    if targets, err = parseDensityTargets(convertTargets); err != nil {
+      return nil, err
+    }
+  }
+  return append(targets, densityTarget{Label: d.Name, Density: d.Screen.Density}), nil
+}
+
+// iOS renders points at 1x, 2x and 3x; a point is close enough to a dp that
+// design specs treat them as equal.
+var iosScales = []densityTarget{
+  {Label: "@1x", Density: MDPI},
+  {Label: "@2x", Density: 2 * MDPI},
+  {Label: "@3x", Density: 3 * MDPI},
+}
+
+func convertOne(input string) error {
+  value, unit, err := parseMeasurement(input)
+  if err != nil {
+    return err
+  }
+  source, err := pxSource()
+  if err != nil {
+    return err
+  }
+  dpValue, err := convertDp(value, unit, source)
+  if err != nil {
+    return err
+  }
+
+  if unit == "px" {
+    fmt.Printf("  %8s: %gdp\n", "dp", math.Round(dpValue*100) / 100)
+  }
+  targets, err := convertDensities(unit == "px")
+  if err != nil {
+    return err
+  }
+  if unit == "sp" {
+    if convertIOS {
+      targets = append(targets, iosScales...)
+    }
+    printFontScales(dpValue, targets, convertFontScales)
+    return nil
+  }
+  for _, target := range targets {
+    var values []string
+    for _, u := range convertUnits {
+      values = append(values, formatUnit(dpValue, target.Density, u))
+    }
+    fmt.Printf("  %8s: %s\n", target.Label, strings.Join(values, " "))
+  }
+  if convertIOS {
+    fmt.Printf("  %8s: %gpt\n", "ios", math.Round(dpValue*100) / 100)
+    for _, scale := range iosScales {
+      fmt.Printf("  %8s: %s\n", scale.Label, formatUnit(dpValue, scale.Density, "px"))
+    }
+  }
+  return nil
+}
+
+// printConversions prints one row per input and one column per density and
+// output unit, or a JSON array. sp values use the first --font-scale, 1 by
+// default.
+func printConversions(inputs []string, format string) error {
+  if format != "table" && format != "csv" && format != "json" {
+    return fmt.Errorf("unknown output format %q, expected table, csv or json", format)
+  }
+  source, err := pxSource()
+  if err != nil {
+    return err
+  }
+  fontScale := 1.0
+  if len(convertFontScales) > 0 {
+    fontScale = convertFontScales[0]
+  }
+  var dpValues []float64
+  hasPx := false
+  for _, input := range inputs {
+    value, unit, err := parseMeasurement(input)
+    if err != nil {
+      return err
+    }
+    dpValue, err := convertDp(value, unit, source)
+    if err != nil {
+      return err
+    }
+    if unit == "sp" {
+      dpValue *= fontScale
+    }
+    dpValues = append(dpValues, dpValue)
+    hasPx = hasPx || unit == "px"
+  }
+  targets, err := convertDensities(hasPx)
+  if err != nil {
+    return err
+  }
+  if convertIOS {
+    targets = append(targets, iosScales...)
+  }
+  if format == "json" {
+    return printConversionsJSON(inputs, dpValues, targets)
+  }
+
+  header := []string{"value"}
+  for _, target := range targets {
+    for _, u := range convertUnits {
+      column := target.Label
+      if len(convertUnits) > 1 || format == "csv" {
+        column += " " + u
+      }
+      header = append(header, column)
+    }
+  }
+  rows := [][]string{header}
+  for i, input := range inputs {
+    row := []string{strings.TrimSpace(input)}
+    for _, target := range targets {
+      for _, u := range convertUnits {
+        cell := formatUnit(dpValues[i], target.Density, u)
+        if format == "csv" {
+          cell = strings.TrimSuffix(cell, u)
+        }
+        row = append(row, cell)
+      }
+    }
+    rows = append(rows, row)
+  }
+
+  if format == "csv" {
+    writer := csv.NewWriter(os.Stdout)
+    writer.WriteAll(rows)
+    return writer.Error()
+  }
+  writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+  for _, row := range rows {
+    fmt.Fprintln(writer, strings.Join(row, "\t") + "\t")
+  }
+  return writer.Flush()
+}
+
+type conversionJSON struct {
+  Value string `json:"value"`
+  Dp float64 `json:"dp"`
+  Densities []densityValuesJSON `json:"densities"`
+}
+
+type densityValuesJSON struct {
+  Density string `json:"density"`
+  Dpi float64 `json:"dpi"`
+  Values map[string]float64 `json:"values"`
+}
+
+func printConversionsJSON(inputs []string, dpValues []float64, targets []densityTarget) error {
+  encoder := json.NewEncoder(os.Stdout)
+  encoder.SetIndent("", "  ")
+  return encoder.Encode(conversionsJSON(inputs, dpValues, targets, convertUnits))
+}
+
+func conversionsJSON(inputs []string, dpValues []float64, targets []densityTarget, units []string) []conversionJSON {
+  conversions := []conversionJSON{}
+  for i, input := range inputs {
+    conversion := conversionJSON{Value: strings.TrimSpace(input), Dp: roundTo(dpValues[i], 2)}
+    for _, target := range targets {
+      values := map[string]float64{}
+      for _, u := range units {
+        values[u] = roundTo(unitValue(dpValues[i], target.Density, u), 2)
+      }
+      conversion.Densities = append(conversion.Densities, densityValuesJSON{Density: target.Label, Dpi: float64(target.Density) * 40, Values: values})
+    }
+    conversions = append(conversions, conversion)
+  }
+  return conversions
+}
+
+func roundTo(value float64, places int) float64 {
+  scale := math.Pow(10, float64(places))
+  return math.Round(value*scale) / scale
+}
+
+func stdinIsPiped() bool {
+  info, err := os.Stdin.Stat()
+  return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
+// readConvertValues reads measurements separated by whitespace or commas from
+// path, or stdin for "" or "-". Lines starting with # are skipped.
+func readConvertValues(path string) (values []string, err error) {
+  in := os.Stdin
+  if path != "" && path != "-" {
+    in, err = os.Open(path)
+    if err != nil { return }
+    defer in.Close()
+  }
+  scanner := bufio.NewScanner(in)
+  for scanner.Scan() {
+    line := strings.TrimSpace(scanner.Text())
+    if strings.HasPrefix(line, "#") {
+      continue
+    }
+    values = append(values, strings.FieldsFunc(line, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })...)
+  }
+  return values, scanner.Err()
+}
+
+// unitValue converts a dp value to unit at density.
+func unitValue(dp float64, density dpi, unit string) float64 {
+  switch unit {
+  case "px":
+    return dp / MDPI * float64(density)
+  case "dp":
+    return dp
+  }
+  return dp / dpPerUnit[unit]
+}
+
+// formatUnit formats a dp value in unit at density.
+func formatUnit(dp float64, density dpi, unit string) string {
+  if unit == "mm" || unit == "in" {
+    return fmt.Sprintf("%.2f%s", unitValue(dp, density, unit), unit)
+  }
+  return fmt.Sprintf("%.1f%s", unitValue(dp, density, unit), unit)
+}
+
+func init() {
+  convertCmd.Flags().StringSliceVar(&convertTargets, "at", nil, "densities to convert for instead of the standard buckets (e.g. 420dpi,tvdpi), or the density of a px value")
+  convertCmd.Flags().StringSliceVar(&convertUnits, "to", []string{"px"}, "units to show per density: px, dp, mm, in, pt")
+  convertCmd.Flags().StringVar(&convertDevice, "device", "", "show values at a known device's density (e.g. pixel8, \"galaxy s23\")")
+  convertCmd.Flags().StringVarP(&convertFile, "file", "f", "", "read values to convert from a file (- for stdin)")
+  convertCmd.Flags().StringVar(&convertOutput, "output", "table", "output format for scripts: table, csv or json")
+  convertCmd.Flags().BoolVar(&convertIOS, "ios", false, "also show iOS points and their @1x, @2x and @3x pixels")
+  convertCmd.Flags().StringVar(&convertGen, "gen", "", "write the values as constants instead: kotlin or java")
+  convertCmd.Flags().StringVar(&convertPackage, "package", "", "package of the generated constants")
+  convertCmd.Flags().StringVar(&convertClass, "class", "Dimens", "name of the generated object or class")
+  convertCmd.Flags().StringVar(&convertGenDir, "gen-dir", ".", "folder to write the generated source into")
+  convertCmd.Flags().Float64SliceVar(&convertFontScales, "font-scale", nil, "font scales to show sp values at (default 0.85,1,1.15,1.3)")
+}
+
+// printFontScales prints a density by font scale table of pixel sizes for an
+// sp value.
+func printFontScales(sp float64, targets []densityTarget, scales []float64) {
+  if len(scales) == 0 {
+    scales = commonFontScales
+  }
+  fmt.Printf("  %8s ", "")
+  for _, scale := range scales {
+    fmt.Printf(" %8s", fmt.Sprintf("%gx", scale))
+  }
+  fmt.Println()
+  for _, target := range targets {
+    fmt.Printf("  %8s:", target.Label)
+    for _, scale := range scales {
+      fmt.Printf(" %8s", fmt.Sprintf("%.1fpx", sp * scale / MDPI * float64(target.Density)))
+    }
+    fmt.Println()
+  }
+}
+
+var measurementUnits = []string{"dp", "sp", "px", "mm", "in", "pt"}
+
+// parseMeasurement reads a value like 30dp, 12.5sp or .5mm. Case and spaces
+// between the number and unit don't matter, and dip is accepted for dp.
+func parseMeasurement(s string) (value float64, unit string, err error) {
+  input := strings.ToLower(strings.TrimSpace(s))
+  if input == "" {
+    return 0, "", errors.New("need a value to convert, ex: 30dp")
+  }
+  split := strings.LastIndexFunc(input, func(r rune) bool { return !unicode.IsLetter(r) }) + 1
+  number, unit := strings.TrimSpace(input[:split]), input[split:]
+  if unit == "dip" {
+    unit = "dp"
+  }
+
+  value, err = strconv.ParseFloat(number, 64)
+  if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+    return 0, "", fmt.Errorf("can't read %q, ex: 30dp, 12.5sp, 96px or 5mm", s)
+  }
+  if unit == "" {
+    return 0, "", fmt.Errorf("%q needs a unit, did you mean %gdp?", s, value)
+  }
+  if indexOf(measurementUnits, unit) < 0 {
+    return 0, "", fmt.Errorf("unknown unit %q in %q, expected %s", unit, s, strings.Join(measurementUnits, ", "))
+  }
+  if value < 0 {
+    return 0, "", fmt.Errorf("%q is negative, sizes can't be", s)
+  }
+  return value, unit, nil
+}
+
+// parseDensityTargets turns --at values into densities, falling back to every
+// configured bucket when none are given.
+func parseDensityTargets(values []string) (targets []densityTarget, err error) {
+  if len(values) == 0 {
+    for _, density := range ascendingDensityList {
+      targets = append(targets, densityTarget{Label: densityToCanonical[density], Density: density})
+    }
+    return
+  }
+
+  for _, value := range values {
+    density, parseErr := parseDensity(value)
+    if parseErr != nil {
+      return nil, parseErr
+    }
+    label := strings.ToLower(strings.TrimSpace(value))
+    if _, numErr := strconv.ParseFloat(label, 64); numErr == nil {
+      label += "dpi"
+    }
+    targets = append(targets, densityTarget{Label: label, Density: density})
+  }
+  return
+}
+
+// parseDensity accepts a bucket name (xhdpi, tvdpi) or a dpi value (420dpi, 420).
+func parseDensity(value string) (dpi, error) {
+  name := strings.ToLower(strings.TrimSpace(value))
+  for density, canonical := range densityToCanonical {
+    if canonical == name {
+      return density, nil
+    }
+  }
+  if androidDpi, ok := namedDensities[name]; ok {
+    return dpi(androidDpi / 40), nil
+  }
+
+  androidDpi, err := strconv.ParseFloat(strings.TrimSuffix(name, "dpi"), 64)
+  if err != nil || androidDpi <= 0 {
+    return 0, fmt.Errorf("unknown density %q", value)
+  }
+  return dpi(androidDpi / 40), nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+  "bytes"
+  "fmt"
+  "image"
+  "log"
+  "os"
+  "path/filepath"
+  "github.com/spf13/cobra"
+)
+
+var (
+  cpTint string
+  cpOpacity float64
+)
+
+var cpCmd = &cobra.Command{
+  Use: "cp <name> <new name>",
+  Short: "Copy a drawable to a new name in every density folder.",
+  Long: `Copy a drawable to a new name in every density folder.
+
+Every density and qualifier folder of every res folder is searched for the
+drawable, and each of its files is copied under the new name, for making
+state variants like a disabled icon. --tint and --opacity recolor or fade
+the copies on the way; without them the files are copied byte for byte.
+
+  andy cp ic_send ic_send_disabled --opacity 0.38
+  andy cp ic_send ic_send_error --tint #B00020`,
+  Args: cobra.ExactArgs(2),
+  Run: func(cmd *cobra.Command, args []string) {
+    name, newName := args[0], args[1]
+    if !fileResourceRegex.MatchString(newName) {
+      log.Fatalf("%s isn't a valid resource name, use lowercase letters, digits and underscores.", newName)
+    }
+    var transforms []transform
+    if cpTint != "" {
+      c, err := parseHexColor(cpTint)
+      if err != nil { log.Fatal(err) }
+      transforms = append(transforms, func(img image.Image) image.Image { return tint(img, c) })
+    }
+    if cmd.Flags().Changed("opacity") {
+      if cpOpacity < 0 || cpOpacity > 1 {
+        log.Fatalf("opacity %g isn't between 0 and 1", cpOpacity)
+      }
+      transforms = append(transforms, func(img image.Image) image.Image { return opacity(img, cpOpacity) })
+    }
+
+    resFolders, err := guessResFolders()
+    if err != nil { log.Fatal(err) }
+    files, err := scanResFiles(resFolders)
+    if err != nil { log.Fatal(err) }
+    named := filesNamed(files, name)
+    if len(named) == 0 {
+      log.Fatalf("no drawable named %s in %v", name, resFolders)
+    }
+    for _, file := range named {
+      if existing := filesNamed(files, file.Type + "/" + newName); len(existing) > 0 {
+        log.Fatalf("%s already exists", existing[0].Path)
+      }
+    }
+
+    for _, file := range named {
+      data, err := copyData(file.Path, transforms)
+      if err != nil { log.Fatal(err) }
+      if err := writeFile(file.Renamed(newName), data); err != nil { log.Fatal(err) }
+    }
+    fmt.Printf("%s %d files\n", green("cp"), len(named))
+  },
+}
+
+func init() {
+  cpCmd.Flags().StringVar(&cpTint, "tint", "", "recolor the copies, as #RRGGBB or #AARRGGBB")
+  cpCmd.Flags().Float64Var(&cpOpacity, "opacity", 1, "scale the alpha of the copies, from 0 to 1")
+}
+
+// copyData is the contents of the copy of the image at path, with transforms
+// applied in order.
+func copyData(path string, transforms []transform) ([]byte, error) {
+  if len(transforms) == 0 {
+    return os.ReadFile(path)
+  }
+  img, format, err := decodeImageFile(path)
+  if err != nil {
+    return nil, fmt.Errorf("%s: %v", path, err)
+  }
+  for _, fn := range transforms {
+    if isNinePatch(filepath.Base(path)) {
+      img = transformNinePatch(img, fn)
+    } else {
+      img = fn(img)
+    }
+  }
+  var buf bytes.Buffer
+  if err := encodeImage(&buf, img, format); err != nil {
+    return nil, err
+  }
+  return buf.Bytes(), nil
+}
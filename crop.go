@@ -0,0 +1,53 @@
+package main
+
+import (
+  "log"
+  "github.com/spf13/cobra"
+)
+
+var (
+  cropPreserveFormat bool
+  cropSourceSets []string
+)
+
+var cropCmd = &cobra.Command{
+  Use: "crop [assets]",
+  Short: "Crop assets with dp geometry (--rect x,y,width,height) and regenerate every density.",
+  Run: func(cmd *cobra.Command, args []string) {
+    if len(args) < 1 {
+      log.Fatal("need one or more filenames.")
+    }
+    if masterOptions.Crop == "" {
+      log.Fatal("need --rect, e.g. --rect 4dp,4dp,40dp,40dp.")
+    }
+    if err := checkOutputOptions(); err != nil {
+      log.Fatal(err)
+    }
+    if !cmd.Flags().Changed("source-set") {
+      cropSourceSets = config.SourceSets
+    }
+
+    for _, arg := range args {
+      drawableInfo, img, err := openDrawable(arg)
+      if err != nil { log.Fatal(err) }
+      if !cropPreserveFormat {
+        drawableInfo.Format = "png"
+      }
+      for _, resFolder := range targetResFolders(drawableInfo.ResFolder, cropSourceSets) {
+        target := drawableInfo
+        target.ResFolder = resFolder
+        if err := variantToFolders(&target, &img, ""); err != nil {
+          log.Fatal(err)
+        }
+      }
+    }
+  },
+}
+
+func init() {
+  addOutputFlags(cropCmd)
+  addMasterFlags(cropCmd)
+  cropCmd.Flags().StringVar(&masterOptions.Crop, "rect", "", "area to keep as x,y,width,height in dp at the asset's density")
+  cropCmd.Flags().BoolVar(&cropPreserveFormat, "preserve-format", false, "write generated assets in the source's format (jpeg, webp) instead of png")
+  cropCmd.Flags().StringSliceVarP(&cropSourceSets, "source-set", "s", nil, "source sets to write generated assets into (e.g. main,paid or all)")
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+  "fmt"
+  "log"
+  "math"
+  "sort"
+  "strconv"
+  "strings"
+  "github.com/spf13/cobra"
+)
+
+var densityCmd = &cobra.Command{
+  Use: "density [WxH] [diagonal]",
+  Short: "Work out a screen's ppi, nearest density bucket and scale factor from its resolution and size.",
+  Long: `Work out a screen's ppi, nearest density bucket and scale factor from its resolution and size.
+
+  andy density 1440x3120 6.7in`,
+  Run: func(cmd *cobra.Command, args []string) {
+    if len(args) != 2 {
+      log.Fatal("need a resolution and diagonal, ex: 1440x3120 6.7in")
+    }
+    width, height, err := parseDpSize(strings.TrimSuffix(strings.ToLower(args[0]), "px"))
+    if err != nil { log.Fatal(err) }
+    diagonal, err := strconv.ParseFloat(strings.TrimRight(strings.ToLower(args[1]), "in\""), 64)
+    if err != nil || diagonal <= 0 {
+      log.Fatalf("can't read diagonal %q, ex: 6.7in", args[1])
+    }
+
+    ppi := math.Hypot(width, height) / diagonal
+    bucket := nearestBucket(ppi)
+    fmt.Printf("%s %gx%gpx, %gin\n", green("density"), width, height, diagonal)
+    fmt.Printf("  %8s: %.1f\n", "ppi", ppi)
+    fmt.Printf("  %8s: %.3gx\n", "scale", ppi / 160)
+    fmt.Printf("  %8s: %s (%gdpi, %gx)\n", "bucket", bucket.Label, float64(bucket.Density) * 40, roundTo(float64(bucket.Density) / MDPI, 4))
+    s := screen{Width: int(width), Height: int(height), Density: bucket.Density}
+    fmt.Printf("  %8s: %dx%ddp (sw%ddp) at %s\n", "size", s.WidthDp(), s.HeightDp(), s.SmallestWidthDp(), bucket.Label)
+  },
+}
+
+// nearestBucket is the named Android density closest to ppi.
+func nearestBucket(ppi float64) densityTarget {
+  var names []string
+  for name := range namedDensities {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+  best := names[0]
+  for _, name := range names {
+    if math.Abs(namedDensities[name] - ppi) < math.Abs(namedDensities[best] - ppi) {
+      best = name
+    }
+  }
+  return densityTarget{Label: best, Density: dpi(namedDensities[best] / 40)}
+}
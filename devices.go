@@ -0,0 +1,81 @@
+package main
+
+import (
+  "fmt"
+  "strings"
+  "unicode"
+)
+
+type device struct {
+  Name string
+  Aliases []string
+  Screen screen
+}
+
+// Screens of common devices at their default display size setting, and the
+// emulator's generic profiles. Densities are Android dpi / 40 like dpi.
+var devices = []device{
+  {Name: "Pixel 3a", Screen: screen{Width: 1080, Height: 2220, Density: 11}},
+  {Name: "Pixel 4a", Screen: screen{Width: 1080, Height: 2340, Density: 11}},
+  {Name: "Pixel 5", Screen: screen{Width: 1080, Height: 2340, Density: 11}},
+  {Name: "Pixel 6", Screen: screen{Width: 1080, Height: 2400, Density: 10.5}},
+  {Name: "Pixel 6 Pro", Screen: screen{Width: 1440, Height: 3120, Density: 14}},
+  {Name: "Pixel 7", Screen: screen{Width: 1080, Height: 2400, Density: 10.5}},
+  {Name: "Pixel 7 Pro", Screen: screen{Width: 1440, Height: 3120, Density: 14}},
+  {Name: "Pixel 8", Screen: screen{Width: 1080, Height: 2400, Density: 10.5}},
+  {Name: "Pixel 8 Pro", Screen: screen{Width: 1344, Height: 2992, Density: 12}},
+  {Name: "Pixel Tablet", Screen: screen{Width: 2560, Height: 1600, Density: 8}},
+  {Name: "Nexus 5", Screen: screen{Width: 1080, Height: 1920, Density: 12}},
+  {Name: "Nexus 5X", Screen: screen{Width: 1080, Height: 1920, Density: 10.5}},
+  {Name: "Nexus 7", Aliases: []string{"nexus 7 2013"}, Screen: screen{Width: 1200, Height: 1920, Density: 8}},
+  {Name: "Nexus 9", Screen: screen{Width: 1536, Height: 2048, Density: 8}},
+  {Name: "Galaxy S21", Aliases: []string{"s21"}, Screen: screen{Width: 1080, Height: 2400, Density: 10.5}},
+  {Name: "Galaxy S22", Aliases: []string{"s22"}, Screen: screen{Width: 1080, Height: 2340, Density: 10.5}},
+  {Name: "Galaxy S23", Aliases: []string{"s23"}, Screen: screen{Width: 1080, Height: 2340, Density: 10.5}},
+  {Name: "Galaxy S23 Ultra", Aliases: []string{"s23 ultra"}, Screen: screen{Width: 1080, Height: 2316, Density: 11.25}},
+  {Name: "Galaxy S24", Aliases: []string{"s24"}, Screen: screen{Width: 1080, Height: 2340, Density: 10.5}},
+  {Name: "Galaxy Tab S8", Aliases: []string{"tab s8"}, Screen: screen{Width: 2560, Height: 1600, Density: 8}},
+  {Name: "Small Phone", Screen: screen{Width: 720, Height: 1280, Density: 8}},
+  {Name: "Medium Phone", Screen: screen{Width: 1080, Height: 2400, Density: 10.5}},
+  {Name: "Medium Tablet", Screen: screen{Width: 2560, Height: 1600, Density: 8}},
+  {Name: "Wear OS Small Round", Screen: screen{Width: 384, Height: 384, Density: 8}},
+  {Name: "Wear OS Large Round", Screen: screen{Width: 454, Height: 454, Density: 8}},
+  {Name: "TV 720p", Screen: screen{Width: 1280, Height: 720, Density: 5.325}},
+  {Name: "TV 1080p", Screen: screen{Width: 1920, Height: 1080, Density: 8}},
+}
+
+// deviceKey ignores case, spaces and punctuation, so "pixel8" finds "Pixel 8".
+func deviceKey(name string) string {
+  return strings.Map(func(r rune) rune {
+    if unicode.IsLetter(r) || unicode.IsDigit(r) {
+      return unicode.ToLower(r)
+    }
+    return -1
+  }, name)
+}
+
+func findDevice(name string) (device, error) {
+  key := deviceKey(name)
+  var similar []string
+  for _, d := range devices {
+    if deviceKey(d.Name) == key {
+      return d, nil
+    }
+    for _, alias := range d.Aliases {
+      if deviceKey(alias) == key {
+        return d, nil
+      }
+    }
+    if key != "" && strings.Contains(deviceKey(d.Name), key) {
+      similar = append(similar, d.Name)
+    }
+  }
+  if len(similar) > 0 {
+    return device{}, fmt.Errorf("unknown device %q, did you mean %s?", name, strings.Join(similar, ", "))
+  }
+  var names []string
+  for _, d := range devices {
+    names = append(names, d.Name)
+  }
+  return device{}, fmt.Errorf("unknown device %q, known devices: %s", name, strings.Join(names, ", "))
+}
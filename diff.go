@@ -0,0 +1,170 @@
+package main
+
+import (
+  "bytes"
+  "fmt"
+  "image"
+  "image/color"
+  "log"
+  "os"
+  "sort"
+  "strings"
+  "github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+  Use: "diff <res folder> <other res folder>",
+  Short: "Compare the drawables of two res folders.",
+  Long: `Compare the drawables of two res folders.
+
+Drawables are matched by name (qualified variants like night on their own)
+and listed when they were added, removed or changed: densities gained or
+lost, sizes in pixels that differ, and pixels that changed, ignoring
+differences too small to see, like a re-encode. Exits nonzero when the
+folders differ, handy for reviewing a designer's drop against the repo.
+
+  andy diff app/src/main/res ~/Downloads/drop/res`,
+  Args: cobra.ExactArgs(2),
+  Run: func(cmd *cobra.Command, args []string) {
+    var sides [2]map[string][]resFile
+    for i, resFolder := range args {
+      if !dirExists(resFolder) {
+        log.Fatalf("res folder %s not found", resFolder)
+      }
+      files, err := scanResFiles([]string{resFolder})
+      if err != nil { log.Fatal(err) }
+      sides[i], _ = groupResources(files)
+    }
+    var names []string
+    for _, side := range sides {
+      for name := range side {
+        if !containsString(names, name) {
+          names = append(names, name)
+        }
+      }
+    }
+    sort.Strings(names)
+
+    added, removed, changed := 0, 0, 0
+    for _, name := range names {
+      before, after := sides[0][name], sides[1][name]
+      switch {
+      case len(before) == 0:
+        added++
+        fmt.Printf("  %s %s (%s)\n", green("added"), name, densityNames(after))
+      case len(after) == 0:
+        removed++
+        fmt.Printf("  %s %s (%s)\n", red("removed"), name, densityNames(before))
+      default:
+        changes, err := diffVariants(before, after)
+        if err != nil { log.Fatal(err) }
+        if len(changes) > 0 {
+          changed++
+          fmt.Printf("  %s %s: %s\n", yellow("changed"), name, strings.Join(changes, ", "))
+        }
+      }
+    }
+    if added + removed + changed == 0 {
+      fmt.Printf("%s %d drawables, no differences\n", green("diff"), len(names))
+      return
+    }
+    fmt.Printf("%s %d added, %d removed, %d changed, %d the same\n", yellow("diff"), added, removed, changed, len(names) - added - removed - changed)
+    os.Exit(1)
+  },
+}
+
+func densityNames(group []resFile) string {
+  var names []string
+  for _, file := range group {
+    names = append(names, densityToCanonical[file.Density])
+  }
+  return strings.Join(names, ", ")
+}
+
+// diffVariants describes how the densities of one drawable changed between
+// before and after.
+func diffVariants(before []resFile, after []resFile) (changes []string, err error) {
+  for _, density := range ascendingDensityList {
+    old, hadOld := densityFile(before, density)
+    file, hasNew := densityFile(after, density)
+    bucket := densityToCanonical[density]
+    switch {
+    case !hadOld && !hasNew:
+    case !hadOld:
+      changes = append(changes, "adds " + bucket)
+    case !hasNew:
+      changes = append(changes, "drops " + bucket)
+    default:
+      change, err := diffImages(old.Path, file.Path)
+      if err != nil {
+        return nil, err
+      }
+      if change != "" {
+        changes = append(changes, bucket + " " + change)
+      }
+    }
+  }
+  return
+}
+
+func densityFile(group []resFile, density dpi) (resFile, bool) {
+  for _, file := range group {
+    if file.Density == density {
+      return file, true
+    }
+  }
+  return resFile{}, false
+}
+
+// diffImages describes how the image at path differs from the one at
+// oldPath, or is empty when they look the same.
+func diffImages(oldPath string, path string) (string, error) {
+  oldData, err := os.ReadFile(oldPath)
+  if err != nil {
+    return "", err
+  }
+  data, err := os.ReadFile(path)
+  if err != nil {
+    return "", err
+  }
+  if bytes.Equal(oldData, data) {
+    return "", nil
+  }
+  oldImg, _, err := image.Decode(bytes.NewReader(oldData))
+  if err != nil {
+    return "", fmt.Errorf("%s: %v", oldPath, err)
+  }
+  img, _, err := image.Decode(bytes.NewReader(data))
+  if err != nil {
+    return "", fmt.Errorf("%s: %v", path, err)
+  }
+  oldBounds, bounds := oldImg.Bounds(), img.Bounds()
+  if oldBounds.Size() != bounds.Size() {
+    return fmt.Sprintf("%dx%d -> %dx%d", oldBounds.Dx(), oldBounds.Dy(), bounds.Dx(), bounds.Dy()), nil
+  }
+  differ := 0
+  for y := 0; y < bounds.Dy(); y++ {
+    for x := 0; x < bounds.Dx(); x++ {
+      if !similarColors(oldImg.At(oldBounds.Min.X + x, oldBounds.Min.Y + y), img.At(bounds.Min.X + x, bounds.Min.Y + y)) {
+        differ++
+      }
+    }
+  }
+  if differ == 0 {
+    return "", nil
+  }
+  return fmt.Sprintf("%.1f%% of pixels changed", 100 * float64(differ) / float64(bounds.Dx() * bounds.Dy())), nil
+}
+
+// similarColors allows for a couple of levels of difference per channel,
+// about what re-encoding or a different optimizer leaves behind.
+func similarColors(a color.Color, b color.Color) bool {
+  r1, g1, b1, a1 := a.RGBA()
+  r2, g2, b2, a2 := b.RGBA()
+  for _, pair := range [][2]uint32{{r1, r2}, {g1, g2}, {b1, b2}, {a1, a2}} {
+    if diff := int64(pair[0]) - int64(pair[1]); diff > 0x0300 || diff < -0x0300 {
+      return false
+    }
+  }
+  return true
+}
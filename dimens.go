@@ -0,0 +1,166 @@
+package main
+
+import (
+  "fmt"
+  "log"
+  "math"
+  "path/filepath"
+  "regexp"
+  "sort"
+  "strconv"
+  "strings"
+  "github.com/spf13/cobra"
+)
+
+const dimensXML = `<?xml version="1.0" encoding="utf-8"?>
+<resources>
+%s</resources>
+`
+
+type dimen struct {
+  Name string
+  Value float64
+  Unit string
+}
+
+type dimensScale struct {
+  Qualifier string
+  Factor float64
+}
+
+var (
+  dimensBase []string
+  dimensScales []string
+  dimensSourceSets []string
+)
+
+var dimensCmd = &cobra.Command{
+  Use: "dimens",
+  Short: "Write values/dimens.xml and scaled overrides for other qualifiers.",
+  Long: `Write values/dimens.xml and scaled overrides for other qualifiers.
+
+Base dimensions are value:name pairs, or the [dimens] table of andy.toml.
+Each --scale writes values-<qualifier>/dimens.xml with every dimension
+multiplied by its factor.
+
+  andy dimens --base 16dp:spacing_large,8dp:spacing_small --scale sw600dp=1.25,sw720dp=1.5`,
+  Run: func(cmd *cobra.Command, args []string) {
+    if err := checkOutputOptions(); err != nil {
+      log.Fatal(err)
+    }
+    if !cmd.Flags().Changed("source-set") {
+      dimensSourceSets = config.SourceSets
+    }
+    dimens, err := parseDimens(dimensBase)
+    if err != nil { log.Fatal(err) }
+    if len(dimensBase) == 0 {
+      dimens, err = configDimens()
+      if err != nil { log.Fatal(err) }
+    }
+    if len(dimens) == 0 {
+      log.Fatal("need base dimensions, ex: --base 16dp:spacing_large")
+    }
+    scales, err := parseDimensScales(dimensScales)
+    if err != nil { log.Fatal(err) }
+    if !cmd.Flags().Changed("scale") && len(config.DimensScales) > 0 {
+      scales = nil
+      for qualifier, factor := range config.DimensScales {
+        scales = append(scales, dimensScale{Qualifier: qualifier, Factor: factor})
+      }
+      sort.Slice(scales, func(i, j int) bool { return scales[i].Qualifier < scales[j].Qualifier })
+    }
+
+    resFolder, err := defaultResFolder()
+    if err != nil { log.Fatal(err) }
+    fmt.Printf("%s %d dimens\n", green("dimens"), len(dimens))
+    for _, target := range targetResFolders(resFolder, dimensSourceSets) {
+      if err := writeDimens(filepath.Join(target, "values"), dimens, 1); err != nil {
+        log.Fatal(err)
+      }
+      for _, scale := range scales {
+        if err := writeDimens(filepath.Join(target, "values-" + scale.Qualifier), dimens, scale.Factor); err != nil {
+          log.Fatal(err)
+        }
+      }
+    }
+  },
+}
+
+func init() {
+  addOutputFlags(dimensCmd)
+  dimensCmd.Flags().StringSliceVar(&dimensBase, "base", nil, "base dimensions as value:name (e.g. 16dp:spacing_large)")
+  dimensCmd.Flags().StringSliceVar(&dimensScales, "scale", []string{"sw600dp=1.25"}, "qualifiers to write scaled overrides for, as qualifier=factor")
+  dimensCmd.Flags().StringSliceVarP(&dimensSourceSets, "source-set", "s", nil, "source sets to write generated assets into (e.g. main,paid or all)")
+}
+
+var resourceNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+func parseDimens(values []string) (dimens []dimen, err error) {
+  for _, value := range values {
+    parts := strings.SplitN(value, ":", 2)
+    if len(parts) != 2 {
+      return nil, fmt.Errorf("can't read dimension %q, ex: 16dp:spacing_large", value)
+    }
+    d, err := newDimen(parts[1], parts[0])
+    if err != nil {
+      return nil, err
+    }
+    dimens = append(dimens, d)
+  }
+  return
+}
+
+// configDimens reads the [dimens] table of the config, sorted by name.
+func configDimens() (dimens []dimen, err error) {
+  for name, value := range config.Dimens {
+    d, err := newDimen(name, value)
+    if err != nil {
+      return nil, fmt.Errorf("%s: %v", configPath, err)
+    }
+    dimens = append(dimens, d)
+  }
+  sort.Slice(dimens, func(i, j int) bool { return dimens[i].Name < dimens[j].Name })
+  return
+}
+
+func newDimen(name string, value string) (dimen, error) {
+  name = strings.TrimSpace(name)
+  if !resourceNameRegex.MatchString(name) {
+    return dimen{}, fmt.Errorf("%q isn't a valid resource name", name)
+  }
+  amount, unit, err := parseMeasurement(value)
+  if err != nil {
+    return dimen{}, err
+  }
+  return dimen{Name: name, Value: amount, Unit: unit}, nil
+}
+
+func parseDimensScales(values []string) (scales []dimensScale, err error) {
+  for _, value := range values {
+    parts := strings.SplitN(value, "=", 2)
+    if len(parts) != 2 || parts[0] == "" {
+      return nil, fmt.Errorf("can't read scale %q, ex: sw600dp=1.25", value)
+    }
+    factor, err := strconv.ParseFloat(parts[1], 64)
+    if err != nil || factor <= 0 {
+      return nil, fmt.Errorf("can't read scale factor %q", parts[1])
+    }
+    scales = append(scales, dimensScale{Qualifier: strings.TrimPrefix(parts[0], "values-"), Factor: factor})
+  }
+  return
+}
+
+// writeDimens writes every dimension multiplied by factor, rounded to
+// hundredths, to folder/dimens.xml.
+func writeDimens(folder string, dimens []dimen, factor float64) error {
+  path := filepath.Join(folder, "dimens.xml")
+  if skipExisting(path) {
+    return nil
+  }
+  items := ""
+  for _, d := range dimens {
+    value := math.Round(d.Value*factor*100) / 100
+    items += fmt.Sprintf("    <dimen name=\"%s\">%s%s</dimen>\n", d.Name, strconv.FormatFloat(value, 'f', -1, 64), d.Unit)
+  }
+  return writeFile(path, []byte(fmt.Sprintf(dimensXML, items)))
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+  "fmt"
+  "image"
+  "io"
+  "path/filepath"
+  "reflect"
+  "sync"
+  "github.com/nfnt/resize"
+)
+
+// DpiOptions are the settings of andy dpi that change what it generates from
+// a source. They're recorded with each source in andy.lock.
+type DpiOptions struct {
+  SourceSets []string `toml:"source_sets,omitempty"`
+  Format string `toml:"format,omitempty"`
+  Quality int `toml:"quality,omitempty"`
+  PreserveFormat bool `toml:"preserve_format,omitempty"`
+  RTL bool `toml:"rtl,omitempty"`
+  Night bool `toml:"night,omitempty"`
+  NightTransform string `toml:"night_transform,omitempty"`
+  NightSource string `toml:"night_source,omitempty"`
+  NightSourceHash string `toml:"night_source_hash,omitempty"`
+  Rounding string `toml:"rounding"`
+  Filter string `toml:"filter,omitempty"`
+  // Only lists the densities to generate, all of them if empty
+  Only []string `toml:"only,omitempty"`
+  Out string `toml:"out,omitempty"`
+  Target string `toml:"target,omitempty"`
+  Master MasterOptions `toml:"master"`
+  Overrides AssetConfig `toml:"overrides"`
+  Densities []float64 `toml:"densities"`
+}
+
+// dpitize generates the lower densities of each asset, jobs at a time, and
+// returns the error of every asset that failed. Sources andy.lock says are
+// up to date are skipped unless regenerate is set.
+func dpitize(assets []string, options DpiOptions, jobs int, regenerate bool) []error {
+  failures := make([]error, len(assets))
+  masterOptions = options.Master
+  outputOptions.Rounding = options.Rounding
+  outputOptions.Filter = options.Filter
+  outputOptions.Out = filepath.FromSlash(options.Out)
+  // a quality locked with one group mustn't carry over to the next
+  encodeQuality = configQuality
+  if options.Quality != 0 {
+    encodeQuality = options.Quality
+  }
+  var toNight transform
+  var err error
+  if len(options.Densities) > 0 && !reflect.DeepEqual(options.Densities, densityValues()) {
+    // the density folders come from the config, they can't be switched per asset
+    err = fmt.Errorf("generated for densities %v, but the config has %v now, run andy dpi on it again", options.Densities, densityValues())
+  } else if options.Night && options.NightSource == "" {
+    toNight, err = parseNightTransform(options.NightTransform)
+  }
+  if err != nil {
+    for i := range failures {
+      failures[i] = err
+    }
+    return failures
+  }
+
+  process := func(arg string) error {
+    info, err := getDrawableInfo(arg)
+    if err != nil {
+      return err
+    }
+    sourcePath := filepath.Join(info.ResFolder, densityToFolder[info.Density], info.Filename)
+    hash, err := fileHash(sourcePath)
+    if err != nil {
+      return err
+    }
+    // each asset can override some options, the lock keeps them per asset
+    options := options
+    if options.Overrides, err = assetConfig(sourcePath); err != nil {
+      return err
+    }
+    skip, err := options.Overrides.skipDensities()
+    if err != nil {
+      return err
+    }
+    if err := skipUnlisted(skip, options.Only); err != nil {
+      return err
+    }
+    if !regenerate && upToDate(sourcePath, hash, options) {
+      printf("%s %s\n", green("unchanged"), sourcePath)
+      if target, ok := outputTargets[options.Target]; ok {
+        lockedTargetAssets(target, sourcePath)
+      }
+      return nil
+    }
+
+    drawableInfo, img, err := openDrawable(arg)
+    if err != nil {
+      return err
+    }
+    if !options.PreserveFormat {
+      drawableInfo.Format = options.Format
+      if options.Overrides.Format != "" {
+        drawableInfo.Format = options.Overrides.Format
+      }
+      if drawableInfo.Format == "" {
+        drawableInfo.Format = "png"
+      }
+    }
+    drawableInfo.Skip = skip
+    drawableInfo.Asset = arg
+    if options.Overrides.NinePatch || isNinePatch(drawableInfo.Filename) {
+      // aapt only takes png nine-patches
+      drawableInfo.NinePatch, drawableInfo.Format = true, "png"
+    }
+    if target, ok := outputTargets[options.Target]; ok {
+      if err := writeTarget(target, drawableInfo, img); err != nil {
+        return err
+      }
+      recordOutputs(arg, sourcePath, hash, options)
+      return nil
+    }
+
+    for _, resFolder := range targetResFolders(drawableInfo.ResFolder, options.SourceSets) {
+      target := drawableInfo
+      target.ResFolder = resFolder
+      if transformsMaster() {
+        err = variantToFolders(&target, &img, "")
+      } else {
+        err = resizeToFolders(&target, &img)
+      }
+      if err == nil && options.RTL {
+        err = mirrorToFolders(&target, &img)
+      }
+      if err != nil {
+        return err
+      }
+    }
+
+    if options.Night {
+      nightImg, err := nightVariant(img, options.NightSource, toNight)
+      if err != nil {
+        return err
+      }
+      for _, resFolder := range targetResFolders(drawableInfo.ResFolder, options.SourceSets) {
+        target := drawableInfo
+        target.ResFolder = resFolder
+        if err := variantToFolders(&target, &nightImg, nightQualifier); err != nil {
+          return err
+        }
+      }
+    }
+
+    // the transformed master replaced the source, so it's locked as the
+    // source without its transforms, or regen would apply them again
+    if transformsMaster() && !outputOptions.DryRun {
+      if written, err := fileHash(sourcePath); err == nil && written != hash {
+        hash, options.Master = written, MasterOptions{}
+      }
+    }
+    recordOutputs(arg, sourcePath, hash, options)
+    return nil
+  }
+
+  // a bad asset doesn't stop the others, its error goes in the summary
+  queue := make(chan int)
+  var workers sync.WaitGroup
+  for i := 0; i < jobs; i++ {
+    workers.Add(1)
+    go func() {
+      defer workers.Done()
+      for i := range queue {
+        if err := process(assets[i]); err != nil {
+          failures[i] = err
+          printf("  %s %s: %v\n", red("fail"), assets[i], err)
+        }
+      }
+    }()
+  }
+  for i := range assets {
+    queue <- i
+  }
+  close(queue)
+  workers.Wait()
+
+  if !outputOptions.DryRun {
+    if err := saveLock(); err != nil {
+      printf("  %s couldn't save %s: %v\n", yellow("warn"), lockFile, err)
+    }
+  }
+  return failures
+}
+
+// recordOutputs locks what was written for the asset at arg, unless some of
+// it was skipped. A transformed master written over the source isn't one of
+// its outputs.
+func recordOutputs(arg string, sourcePath string, hash string, options DpiOptions) {
+  outputMutex.Lock()
+  counts := outputCounts[arg]
+  outputMutex.Unlock()
+  if outputOptions.DryRun || counts == nil || counts.Skipped > 0 {
+    return
+  }
+  outputs := map[string]string{}
+  for path, outputHash := range counts.Hashes {
+    if projectPath(path) != projectPath(sourcePath) {
+      outputs[path] = outputHash
+    }
+  }
+  recordBuild(sourcePath, hash, options, outputs)
+}
+
+// skipUnlisted adds every density not in only to skip, if only lists any.
+func skipUnlisted(skip map[dpi]bool, only []string) error {
+  if len(only) == 0 {
+    return nil
+  }
+  listed := map[dpi]bool{}
+  for _, name := range only {
+    density, err := parseDensity(name)
+    if err != nil {
+      return err
+    }
+    listed[density] = true
+  }
+  for _, density := range ascendingDensityList {
+    if !listed[density] {
+      skip[density] = true
+    }
+  }
+  return nil
+}
+
+// densityValues lists the configured densities in Android dpi.
+func densityValues() (values []float64) {
+  for _, density := range ascendingDensityList {
+    values = append(values, float64(density) * 40)
+  }
+  return
+}
+
+// dpiStream reads an image drawn at density from from r and writes it to w
+// resized for density to, in format or the one it was read in if that's "",
+// for andy dpi - in pipelines. Nothing but the image goes to w.
+func dpiStream(r io.Reader, w io.Writer, from dpi, to dpi, format string) error {
+  img, sourceFormat, err := image.Decode(r)
+  if err != nil {
+    return fmt.Errorf("stdin: %v", err)
+  }
+  if img, err = prepareMaster(img, from); err != nil {
+    return err
+  }
+  if format == "" {
+    format = sourceFormat
+  }
+  return encodeImage(w, scaleImage(img, from, to), format)
+}
+
+// scaleImage resizes img drawn at density from for density to.
+func scaleImage(img image.Image, from dpi, to dpi) image.Image {
+  if to == from {
+    return img
+  }
+  width, height := getDimens(&img)
+  targetWidth, _ := scaleDimension(width, from, to)
+  targetHeight, _ := scaleDimension(height, from, to)
+  return resize.Resize(uint(targetWidth), uint(targetHeight), img, resizeFilter())
+}
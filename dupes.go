@@ -0,0 +1,130 @@
+package main
+
+import (
+  "fmt"
+  "log"
+  "math"
+  "math/bits"
+  "github.com/nfnt/resize"
+  "github.com/spf13/cobra"
+)
+
+var dupesThreshold int
+
+var dupesCmd = &cobra.Command{
+  Use: "dupes [res folders]",
+  Short: "Find drawables that look the same under different names.",
+  Long: `Find drawables that look the same under different names.
+
+The highest density of every drawable is reduced to a 64 bit perceptual hash,
+and drawables of the same aspect ratio whose hashes differ in at most
+--threshold bits are listed together, so redundant assets can be
+consolidated.
+
+  andy dupes
+  andy dupes --threshold 0`,
+  Run: func(cmd *cobra.Command, args []string) {
+    if len(args) > 0 {
+      resDirs = args
+    }
+    resFolders, err := guessResFolders()
+    if err != nil { log.Fatal(err) }
+    files, err := scanResFiles(resFolders)
+    if err != nil { log.Fatal(err) }
+
+    var sources []resFile
+    var hashes []imageHash
+    groups, names := groupResources(files)
+    for _, name := range names {
+      source := groups[name][len(groups[name]) - 1]
+      hash, err := perceptualHash(source.Path)
+      if err != nil { log.Fatal(err) }
+      sources, hashes = append(sources, source), append(hashes, hash)
+    }
+
+    sets := 0
+    grouped := make([]bool, len(sources))
+    for i := range sources {
+      if grouped[i] {
+        continue
+      }
+      set := []resFile{sources[i]}
+      for j := i + 1; j < len(sources); j++ {
+        if !grouped[j] && sources[j].Name != sources[i].Name && hashes[i].like(hashes[j]) {
+          grouped[j] = true
+          set = append(set, sources[j])
+        }
+      }
+      if len(set) < 2 {
+        continue
+      }
+      sets++
+      fmt.Printf("%s %d drawables look the same\n", yellow("dupes"), len(set))
+      for _, file := range set {
+        fmt.Printf("  %s\n", file.Path)
+      }
+    }
+    if sets == 0 {
+      fmt.Printf("%s no duplicates in %d drawables\n", green("dupes"), len(sources))
+    }
+  },
+}
+
+func init() {
+  dupesCmd.Flags().IntVar(&dupesThreshold, "threshold", 2, "bits two hashes may differ in and still count as the same")
+}
+
+// imageHash is a difference hash of an image with its average color, since
+// flat images of any color have the same difference hash, and its aspect
+// ratio, which the hash loses when the image is shrunk to 9x8.
+type imageHash struct {
+  Bits uint64
+  Color [4]float64
+  Aspect float64
+}
+
+// maxAspectDifference is how far apart, relatively, the aspect ratios of two
+// images that look the same can be.
+const maxAspectDifference = 0.05
+
+func (hash imageHash) like(other imageHash) bool {
+  if math.Abs(hash.Aspect / other.Aspect - 1) > maxAspectDifference {
+    return false
+  }
+  for i := range hash.Color {
+    if math.Abs(hash.Color[i] - other.Color[i]) > 0x0800 {
+      return false
+    }
+  }
+  return bits.OnesCount64(hash.Bits ^ other.Bits) <= dupesThreshold
+}
+
+// perceptualHash hashes the image at path shrunk to 9x8: each bit tells
+// whether a pixel is brighter than the one to its right. Transparency
+// counts, so shapes on transparent backgrounds hash apart.
+func perceptualHash(path string) (hash imageHash, err error) {
+  img, _, err := decodeImageFile(path)
+  if err != nil {
+    return hash, err
+  }
+  width, height := getDimens(&img)
+  hash.Aspect = float64(width) / float64(height)
+  small := resize.Resize(9, 8, img, resize.Bilinear)
+  bounds := small.Bounds()
+  for y := 0; y < 8; y++ {
+    for x := 0; x < 9; x++ {
+      r, g, b, a := small.At(bounds.Min.X + x, bounds.Min.Y + y).RGBA()
+      for i, channel := range []uint32{r, g, b, a} {
+        hash.Color[i] += float64(channel) / 72
+      }
+      if x == 8 {
+        continue
+      }
+      hash.Bits <<= 1
+      if lumaAlpha(small, bounds.Min.X + x, bounds.Min.Y + y) > lumaAlpha(small, bounds.Min.X + x + 1, bounds.Min.Y + y) {
+        hash.Bits |= 1
+      }
+    }
+  }
+  return hash, nil
+}
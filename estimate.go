@@ -0,0 +1,161 @@
+package main
+
+import (
+  "bytes"
+  "fmt"
+  "log"
+  "os"
+  "path/filepath"
+  "runtime"
+  "sort"
+  "strings"
+  "sync"
+  "text/tabwriter"
+  "github.com/spf13/cobra"
+)
+
+var (
+  estimateWebp bool
+  estimateSample int
+  estimateJobs int
+)
+
+var estimateCmd = &cobra.Command{
+  Use: "estimate --webp [res folders]",
+  Short: "Estimate the bytes converting the png drawables to webp would save.",
+  Long: `Estimate the bytes converting the png drawables to webp would save.
+
+The png drawables of every density folder are encoded as lossless webp in
+memory, nothing is written, and the savings are reported per folder. Files
+that come out larger count as staying png, like Android Studio's converter
+leaves them. Nine-patches can't be webp and are left out.
+
+--sample only encodes that many files per folder and scales their savings
+to the whole folder, for a quick estimate of a big project.
+
+  andy estimate --webp
+  andy estimate --webp --sample 20`,
+  Run: func(cmd *cobra.Command, args []string) {
+    if !estimateWebp {
+      log.Fatal("nothing to estimate, pass --webp.")
+    }
+    if estimateJobs < 1 {
+      log.Fatal("--jobs must be at least 1.")
+    }
+    if len(args) > 0 {
+      resDirs = args
+    }
+    resFolders, err := guessResFolders()
+    if err != nil { log.Fatal(err) }
+    files, err := scanResFiles(resFolders)
+    if err != nil { log.Fatal(err) }
+
+    folders := map[string][]resFile{}
+    for _, file := range files {
+      if strings.EqualFold(filepath.Ext(file.Path), ".png") && !isNinePatch(filepath.Base(file.Path)) {
+        folder := filepath.Dir(file.Path)
+        folders[folder] = append(folders[folder], file)
+      }
+    }
+    if len(folders) == 0 {
+      log.Fatal("no png drawables to estimate.")
+    }
+    var names []string
+    for folder := range folders {
+      names = append(names, folder)
+    }
+    sort.Strings(names)
+
+    writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    fmt.Fprintf(writer, "folder\tfiles\tpng\twebp\tsaved\t\n")
+    var totalPng, totalWebp int64
+    count := 0
+    for _, folder := range names {
+      pngSize, webpSize, err := estimateFolder(folders[folder])
+      if err != nil { log.Fatal(err) }
+      totalPng += pngSize
+      count += len(folders[folder])
+      totalWebp += webpSize
+      fmt.Fprintf(writer, "%s\t%d\t%s\t%s\t%s\t\n", folder, len(folders[folder]), formatBytes(pngSize), formatBytes(webpSize), savings(pngSize, webpSize))
+    }
+    fmt.Fprintf(writer, "total\t%d\t%s\t%s\t%s\t\n", count, formatBytes(totalPng), formatBytes(totalWebp), savings(totalPng, totalWebp))
+    writer.Flush()
+  },
+}
+
+func init() {
+  estimateCmd.Flags().BoolVar(&estimateWebp, "webp", false, "estimate converting png drawables to lossless webp")
+  estimateCmd.Flags().IntVar(&estimateSample, "sample", 0, "files to encode per folder, 0 for all of them")
+  estimateCmd.Flags().IntVarP(&estimateJobs, "jobs", "j", runtime.NumCPU(), "number of files to encode at once")
+}
+
+func savings(before int64, after int64) string {
+  if before == 0 {
+    return "-"
+  }
+  return fmt.Sprintf("%s (%.0f%%)", formatBytes(before - after), 100 * float64(before - after) / float64(before))
+}
+
+// estimateFolder is the bytes the png files of a folder take now and would
+// take with each as webp where that's smaller, scaled up from --sample files
+// spread over the folder if it's set.
+func estimateFolder(files []resFile) (pngSize int64, webpSize int64, err error) {
+  sample := files
+  if estimateSample > 0 && len(files) > estimateSample {
+    sample = nil
+    for i := 0; i < estimateSample; i++ {
+      sample = append(sample, files[i * len(files) / estimateSample])
+    }
+  }
+
+  encoded := make([]int64, len(sample))
+  errs := make([]error, len(sample))
+  queue := make(chan int)
+  var workers sync.WaitGroup
+  for i := 0; i < estimateJobs; i++ {
+    workers.Add(1)
+    go func() {
+      defer workers.Done()
+      for i := range queue {
+        encoded[i], errs[i] = encodedWebpSize(sample[i])
+      }
+    }()
+  }
+  for i := range sample {
+    queue <- i
+  }
+  close(queue)
+  workers.Wait()
+
+  var samplePng, sampleWebp int64
+  for i, file := range sample {
+    if errs[i] != nil {
+      return 0, 0, fmt.Errorf("%s: %v", file.Path, errs[i])
+    }
+    samplePng += file.Size
+    if encoded[i] < file.Size {
+      sampleWebp += encoded[i]
+    } else {
+      sampleWebp += file.Size
+    }
+  }
+  for _, file := range files {
+    pngSize += file.Size
+  }
+  if samplePng == 0 {
+    return pngSize, pngSize, nil
+  }
+  return pngSize, int64(float64(pngSize) * float64(sampleWebp) / float64(samplePng)), nil
+}
+
+func encodedWebpSize(file resFile) (int64, error) {
+  img, _, err := decodeImageFile(file.Path)
+  if err != nil {
+    return 0, err
+  }
+  var buf bytes.Buffer
+  if err := encodeImage(&buf, img, "webp"); err != nil {
+    return 0, err
+  }
+  return int64(buf.Len()), nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+  "encoding/json"
+  "fmt"
+  "image"
+  "log"
+  "path/filepath"
+  "strings"
+  "github.com/nfnt/resize"
+  "github.com/spf13/cobra"
+)
+
+var (
+  exportCatalog string
+  exportName string
+)
+
+var exportCmd = &cobra.Command{
+  Use: "export",
+  Short: "Write assets for other platforms from the same masters.",
+}
+
+var exportIOSCmd = &cobra.Command{
+  Use: "ios <drawable>...",
+  Short: "Write iOS asset catalog imagesets from drawables.",
+  Long: `Write iOS asset catalog imagesets from drawables.
+
+Each drawable's highest density is the master, like for andy dpi, and is
+resized to 1x, 2x and 3x PNGs in an imageset of the --catalog folder along
+with its Contents.json. 1x is the size of mdpi, so a 24dp icon is 24pt. The
+master flags (--trim, --square, --rotate, --flip) apply here too.
+
+  andy export ios ic_send.png
+  andy export ios ic_send.png ic_close.png --catalog ios/App/Assets.xcassets`,
+  Args: cobra.MinimumNArgs(1),
+  Run: func(cmd *cobra.Command, args []string) {
+    if err := checkOutputOptions(); err != nil { log.Fatal(err) }
+    if exportName != "" && len(args) > 1 {
+      log.Fatal("--name only works with one drawable.")
+    }
+    for _, arg := range args {
+      if err := exportImageset(arg); err != nil { log.Fatal(err) }
+    }
+  },
+}
+
+func init() {
+  addOutputFlags(exportIOSCmd)
+  addMasterFlags(exportIOSCmd)
+  exportIOSCmd.Flags().StringVar(&exportCatalog, "catalog", "Assets.xcassets", "asset catalog to write the imagesets into")
+  exportIOSCmd.Flags().StringVar(&exportName, "name", "", "name of the imageset, the drawable's name by default")
+  exportCmd.AddCommand(exportIOSCmd)
+}
+
+type catalogImage struct {
+  Filename string `json:"filename"`
+  Idiom string `json:"idiom"`
+  Scale string `json:"scale"`
+  Appearances []map[string]string `json:"appearances,omitempty"`
+}
+
+type catalogInfo struct {
+  Author string `json:"author"`
+  Version int `json:"version"`
+}
+
+type catalogContents struct {
+  Images []catalogImage `json:"images,omitempty"`
+  Info catalogInfo `json:"info"`
+}
+
+func writeContents(path string, contents catalogContents) error {
+  contents.Info = catalogInfo{Author: "xcode", Version: 1}
+  data, err := json.MarshalIndent(contents, "", "  ")
+  if err != nil {
+    return err
+  }
+  return writeFile(path, append(data, '\n'))
+}
+
+// writeCatalogContents writes the Contents.json Xcode expects at the root of
+// an asset catalog, unless there is one.
+func writeCatalogContents(catalog string) error {
+  path := filepath.Join(catalog, "Contents.json")
+  if fileExists(path) {
+    return nil
+  }
+  return writeContents(path, catalogContents{})
+}
+
+// exportImageset writes the 1x, 2x and 3x images of the drawable at arg as
+// an imageset.
+func exportImageset(arg string) error {
+  info, img, err := openDrawable(arg)
+  if err != nil {
+    return err
+  }
+  if isNinePatch(info.Filename) {
+    return fmt.Errorf("%s: nine-patches have no iOS equivalent, use slicing in Xcode instead", arg)
+  }
+  name := exportName
+  if name == "" {
+    name = strings.TrimSuffix(info.Filename, filepath.Ext(info.Filename))
+  }
+  if err := writeCatalogContents(exportCatalog); err != nil {
+    return err
+  }
+  dir := filepath.Join(exportCatalog, name + ".imageset")
+  width, height := getDimens(&img)
+  var contents catalogContents
+  for _, scale := range iosScales {
+    density := scale.Density
+    filename := name + ".png"
+    if density > MDPI {
+      filename = name + scale.Label + ".png"
+    }
+    path := filepath.Join(dir, filename)
+    if density > info.Density {
+      printf("  %s %s is upscaled from %s\n", yellow("warn"), path, densityToCanonical[info.Density])
+    }
+    var resized image.Image = img
+    if density != info.Density {
+      targetWidth, _ := scaleDimension(width, info.Density, density)
+      targetHeight, _ := scaleDimension(height, info.Density, density)
+      resized = resize.Resize(uint(targetWidth), uint(targetHeight), img, resizeFilter())
+    }
+    if !skipExisting(path) {
+      if err := writeImage(path, resized, "png"); err != nil {
+        return err
+      }
+    }
+    contents.Images = append(contents.Images, catalogImage{Filename: filename, Idiom: "universal", Scale: strings.TrimPrefix(scale.Label, "@")})
+  }
+  return writeContents(filepath.Join(dir, "Contents.json"), contents)
+}
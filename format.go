@@ -0,0 +1,90 @@
+package main
+
+import (
+  "fmt"
+  "image"
+  "image/jpeg"
+  "image/png"
+  "io"
+  "os"
+  "path/filepath"
+  "strings"
+  "github.com/HugoSmits86/nativewebp"
+)
+
+var formatExtensions = map[string]string{
+  "png":  ".png",
+  "jpeg": ".jpg",
+  "webp": ".webp",
+}
+
+func decodeImageFile(path string) (img image.Image, format string, err error) {
+  file, err := os.Open(path)
+  if err != nil { return }
+  defer file.Close()
+
+  img, format, err = image.Decode(file)
+  if err != nil {
+    err = fmt.Errorf("%s: %v", path, err)
+  }
+  return
+}
+
+// encodeQuality is the jpeg quality and pngEncoder the png compression of
+// every image written, set from [optimize] in the config. configQuality is
+// what it was set to there, for sources locked without a quality.
+var (
+  encodeQuality = 90
+  configQuality = 90
+  pngEncoder = png.Encoder{}
+)
+
+var pngCompressionLevels = map[string]png.CompressionLevel{
+  "default": png.DefaultCompression,
+  "none": png.NoCompression,
+  "fast": png.BestSpeed,
+  "best": png.BestCompression,
+}
+
+func setEncodeOptions(quality int, compression string) error {
+  if quality != 0 {
+    if quality < 1 || quality > 100 {
+      return fmt.Errorf("quality %d should be between 1 and 100", quality)
+    }
+    encodeQuality = quality
+  }
+  if compression != "" {
+    level, ok := pngCompressionLevels[compression]
+    if !ok {
+      return fmt.Errorf("unknown png compression %q, expected default, none, fast or best", compression)
+    }
+    pngEncoder.CompressionLevel = level
+  }
+  return nil
+}
+
+func encodeImage(w io.Writer, img image.Image, format string) error {
+  switch format {
+  case "", "png":
+    return pngEncoder.Encode(w, img)
+  case "jpeg":
+    return jpeg.Encode(w, img, &jpeg.Options{Quality: encodeQuality})
+  case "webp":
+    return nativewebp.Encode(w, img, nil)
+  }
+  return fmt.Errorf("unsupported image format %q", format)
+}
+
+// withFormatExtension swaps the extension of filename to match format, keeping
+// equivalent spellings such as .jpeg for jpeg output.
+func withFormatExtension(filename string, format string) string {
+  if format == "" {
+    format = "png"
+  }
+  ext := filepath.Ext(filename)
+  lower := strings.ToLower(ext)
+  if lower == formatExtensions[format] || (format == "jpeg" && lower == ".jpeg") {
+    return filename
+  }
+  return strings.TrimSuffix(filename, ext) + formatExtensions[format]
+}
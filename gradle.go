@@ -0,0 +1,120 @@
+package main
+
+import (
+  "fmt"
+  "log"
+  "strings"
+  "github.com/spf13/cobra"
+)
+
+const kotlinGradleScript = `// Generated by andy gradle-init. Apply it from the app's build.gradle.kts:
+//   apply(from = rootProject.file(%[1]s))
+
+val andyRegen by tasks.registering(Exec::class) {
+    group = "andy"
+    description = "Regenerates the densities of masters that changed since andy.lock."
+    workingDir = rootDir
+    commandLine(%[2]s, "regen", "--changed")
+}
+
+val andyCheck by tasks.registering(Exec::class) {
+    group = "andy"
+    description = "Fails if generated assets no longer match andy.lock."
+    workingDir = rootDir
+    commandLine(%[2]s, "check", "--generated")
+    mustRunAfter(andyRegen)
+}
+
+// CI only checks, so stale assets fail the build instead of being fixed
+tasks.matching { it.name == "preBuild" }.configureEach {
+    if (System.getenv("CI") == null) {
+        dependsOn(andyRegen)
+    }
+    dependsOn(andyCheck)
+}
+`
+
+const groovyGradleScript = `// Generated by andy gradle-init. Apply it from the app's build.gradle:
+//   apply from: rootProject.file(%[1]s)
+
+def andyRegen = tasks.register('andyRegen', Exec) {
+    group = 'andy'
+    description = 'Regenerates the densities of masters that changed since andy.lock.'
+    workingDir = rootDir
+    commandLine %[2]s, 'regen', '--changed'
+}
+
+def andyCheck = tasks.register('andyCheck', Exec) {
+    group = 'andy'
+    description = 'Fails if generated assets no longer match andy.lock.'
+    workingDir = rootDir
+    commandLine %[2]s, 'check', '--generated'
+    mustRunAfter andyRegen
+}
+
+// CI only checks, so stale assets fail the build instead of being fixed
+tasks.matching { it.name == 'preBuild' }.configureEach {
+    if (System.getenv('CI') == null) {
+        dependsOn andyRegen
+    }
+    dependsOn andyCheck
+}
+`
+
+var (
+  gradleDSL string
+  gradleOut string
+  gradleAndy string
+)
+
+var gradleInitCmd = &cobra.Command{
+  Use: "gradle-init",
+  Short: "Write a Gradle script that runs andy before every build.",
+  Long: `Write a Gradle script that runs andy before every build.
+
+The script registers an andyRegen task running andy regen --changed and an
+andyCheck task running andy check --generated, and makes preBuild depend on
+both, so the densities of changed masters are brought up to date on
+developer machines while hand-edited outputs fail the check. When the
+CI environment variable is set only andyCheck runs, failing the build if
+generated assets are stale. Both run in the root project's folder, where
+andy.lock is, so run gradle-init there and apply the script from the app's
+build file as its first line says.
+
+  andy gradle-init
+  andy gradle-init --dsl groovy`,
+  Run: func(cmd *cobra.Command, args []string) {
+    if err := checkOutputOptions(); err != nil { log.Fatal(err) }
+    var script string
+    switch gradleDSL {
+    case "kotlin":
+      script = kotlinGradleScript
+    case "groovy":
+      script = groovyGradleScript
+    default:
+      log.Fatalf("unknown --dsl %q, expected kotlin or groovy", gradleDSL)
+    }
+    if gradleOut == "" {
+      gradleOut = "andy.gradle"
+      if gradleDSL == "kotlin" {
+        gradleOut += ".kts"
+      }
+    }
+    quote := `"`
+    if gradleDSL == "groovy" {
+      quote = `'`
+    }
+    literal := func(s string) string {
+      return quote + strings.NewReplacer(`\`, `\\`, quote, `\` + quote, "$", `\$`).Replace(s) + quote
+    }
+    if err := writeFile(gradleOut, []byte(fmt.Sprintf(script, literal(gradleOut), literal(gradleAndy)))); err != nil { log.Fatal(err) }
+  },
+}
+
+func init() {
+  // --out names the script here, so it's taken before addOutputFlags
+  gradleInitCmd.Flags().StringVar(&gradleOut, "out", "", "script to write (default andy.gradle.kts, or andy.gradle for groovy)")
+  addOutputFlags(gradleInitCmd)
+  gradleInitCmd.Flags().StringVar(&gradleDSL, "dsl", "kotlin", "Gradle build language: kotlin or groovy")
+  gradleInitCmd.Flags().StringVar(&gradleAndy, "andy", "andy", "andy command the tasks run, if it isn't on the PATH")
+}
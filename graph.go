@@ -0,0 +1,111 @@
+package main
+
+import (
+  "fmt"
+  "log"
+  "path/filepath"
+  "sort"
+  "strconv"
+  "strings"
+  "github.com/spf13/cobra"
+)
+
+var graphFormat string
+
+var graphCmd = &cobra.Command{
+  Use: "graph [res folders]",
+  Short: "Print which layouts, menus, styles and code reference which drawables.",
+  Long: `Print which layouts, menus, styles and code reference which drawables.
+
+Every xml, Kotlin and Java file under the working directory is searched like
+andy unused does, and each reference becomes an edge from the file to the
+drawable or mipmap, including drawables that reference others from their
+xml. Resource files are named like their resources, e.g. layout/main. Bitmap
+drawables nothing references are included on their own, drawn gray, so
+they're easy to spot and prune. Render it with Graphviz.
+
+  andy graph --format dot | dot -Tsvg > drawables.svg`,
+  Run: func(cmd *cobra.Command, args []string) {
+    if graphFormat != "dot" {
+      log.Fatalf("unknown graph format %q, expected dot", graphFormat)
+    }
+    if len(args) > 0 {
+      resDirs = args
+    }
+    resFolders, err := guessResFolders()
+    if err != nil { log.Fatal(err) }
+    files, err := scanResFiles(resFolders)
+    if err != nil { log.Fatal(err) }
+
+    edges := map[[2]string]bool{}
+    err = walkSources(".", func(path string, data []byte) error {
+      from := graphNode(path)
+      for _, match := range referenceRegex.FindAllSubmatch(data, -1) {
+        to := string(match[1]) + "/" + string(match[2])
+        if to != from {
+          edges[[2]string{from, to}] = true
+        }
+      }
+      return nil
+    })
+    if err != nil { log.Fatal(err) }
+    printGraph(edges, files)
+  },
+}
+
+func init() {
+  graphCmd.Flags().StringVar(&graphFormat, "format", "dot", "graph format: dot")
+}
+
+// resTypes are the folders of a res tree that hold xml that can reference
+// drawables.
+var resTypes = []string{"anim", "animator", "color", "drawable", "layout", "menu", "mipmap", "navigation", "transition", "values", "xml"}
+
+// graphNode names the file at path in the graph: resource files by their
+// type and name, like layout/main, and anything else by its path.
+func graphNode(path string) string {
+  folder := strings.SplitN(filepath.Base(filepath.Dir(path)), "-", 2)[0]
+  if filepath.Ext(path) == ".xml" && containsString(resTypes, folder) {
+    return folder + "/" + strings.TrimSuffix(filepath.Base(path), ".xml")
+  }
+  if filepath.Base(path) == "AndroidManifest.xml" {
+    return "manifest"
+  }
+  return filepath.ToSlash(path)
+}
+
+func printGraph(edges map[[2]string]bool, files []resFile) {
+  var lines []string
+  referenced := map[string]bool{}
+  nodes := map[string]bool{}
+  for edge := range edges {
+    lines = append(lines, fmt.Sprintf("  %s -> %s;", strconv.Quote(edge[0]), strconv.Quote(edge[1])))
+    referenced[edge[1]] = true
+    nodes[edge[0]], nodes[edge[1]] = true, true
+  }
+  for _, file := range files {
+    if key := file.resourceKey(); !referenced[key] && !nodes[key] {
+      nodes[key] = true
+      lines = append(lines, fmt.Sprintf("  %s [color=gray, fontcolor=gray];", strconv.Quote(key)))
+    }
+  }
+  var resources []string
+  for node := range nodes {
+    if strings.HasPrefix(node, "drawable/") || strings.HasPrefix(node, "mipmap/") {
+      resources = append(resources, node)
+    }
+  }
+  sort.Strings(resources)
+  sort.Strings(lines)
+
+  fmt.Println("digraph andy {")
+  fmt.Println("  rankdir=LR;")
+  fmt.Println("  node [shape=ellipse];")
+  for _, node := range resources {
+    fmt.Printf("  %s [shape=box];\n", strconv.Quote(node))
+  }
+  for _, line := range lines {
+    fmt.Println(line)
+  }
+  fmt.Println("}")
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+  "bytes"
+  "context"
+  "io"
+  "github.com/mcginty/andy/andypb"
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+)
+
+// grpcChunk is how much of an image goes in each streamed message.
+const grpcChunk = 64 << 10
+
+// andyServer implements andypb.AndyServer with what the HTTP endpoints use.
+type andyServer struct {
+  andypb.UnimplementedAndyServer
+}
+
+func newGRPCServer() *grpc.Server {
+  server := grpc.NewServer(grpc.ChainUnaryInterceptor(logUnary), grpc.ChainStreamInterceptor(logStream))
+  andypb.RegisterAndyServer(server, andyServer{})
+  return server
+}
+
+func logUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+  resp, err := handler(ctx, req)
+  logCall(info.FullMethod, err)
+  return resp, err
+}
+
+func logStream(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+  err := handler(srv, stream)
+  logCall(info.FullMethod, err)
+  return err
+}
+
+func logCall(method string, err error) {
+  if err != nil {
+    printf("  %s grpc %s: %v\n", red("fail"), method, status.Convert(err).Message())
+    return
+  }
+  printf("  %s grpc %s\n", green("ok"), method)
+}
+
+// receiveMaster reads an upload: the master's options, then its image.
+func receiveMaster(stream interface{ Recv() (*andypb.Upload, error) }) (*andypb.Master, []byte, dpi, error) {
+  first, err := stream.Recv()
+  if err != nil {
+    return nil, nil, 0, err
+  }
+  master := first.GetMaster()
+  if master == nil {
+    return nil, nil, 0, status.Error(codes.InvalidArgument, "send the master's options before its image")
+  }
+  if master.Density == "" {
+    return nil, nil, 0, status.Error(codes.InvalidArgument, "need the density the image was drawn at, ex: xxxhdpi")
+  }
+  density, err := parseDensity(master.Density)
+  if err != nil {
+    return nil, nil, 0, status.Error(codes.InvalidArgument, err.Error())
+  }
+  var data bytes.Buffer
+  for {
+    upload, err := stream.Recv()
+    if err == io.EOF {
+      break
+    }
+    if err != nil {
+      return nil, nil, 0, err
+    }
+    if data.Len() + len(upload.GetChunk()) > maxUpload {
+      return nil, nil, 0, status.Errorf(codes.ResourceExhausted, "masters can be at most %s", formatBytes(maxUpload))
+    }
+    data.Write(upload.GetChunk())
+  }
+  return master, data.Bytes(), density, nil
+}
+
+func (andyServer) Dpi(stream grpc.BidiStreamingServer[andypb.Upload, andypb.Generated]) error {
+  master, data, from, err := receiveMaster(stream)
+  if err != nil {
+    return err
+  }
+  img, format, err := decodeUpload(data)
+  if err != nil {
+    return status.Error(codes.InvalidArgument, err.Error())
+  }
+  images, err := densitySet(img, format, from, master.Name, master.Format)
+  if err != nil {
+    return status.Error(codes.InvalidArgument, err.Error())
+  }
+  for _, generated := range images {
+    header := &andypb.Image{Path: generated.Path, Density: densityToCanonical[generated.Density], Width: int32(generated.Size.X), Height: int32(generated.Size.Y), Bytes: int64(len(generated.Data))}
+    if err := stream.Send(&andypb.Generated{Part: &andypb.Generated_Image{Image: header}}); err != nil {
+      return err
+    }
+    for start := 0; start < len(generated.Data); start += grpcChunk {
+      end := start + grpcChunk
+      if end > len(generated.Data) {
+        end = len(generated.Data)
+      }
+      if err := stream.Send(&andypb.Generated{Part: &andypb.Generated_Chunk{Chunk: generated.Data[start:end]}}); err != nil {
+        return err
+      }
+    }
+  }
+  return nil
+}
+
+func (andyServer) Convert(ctx context.Context, req *andypb.ConvertRequest) (*andypb.ConvertResponse, error) {
+  conversions, err := convertValues(req.Values, req.At, req.To)
+  if err != nil {
+    return nil, status.Error(codes.InvalidArgument, err.Error())
+  }
+  resp := &andypb.ConvertResponse{}
+  for _, conversion := range conversions {
+    converted := &andypb.Conversion{Value: conversion.Value, Dp: conversion.Dp}
+    for _, density := range conversion.Densities {
+      converted.Densities = append(converted.Densities, &andypb.DensityValues{Density: density.Density, Dpi: density.Dpi, Values: density.Values})
+    }
+    resp.Conversions = append(resp.Conversions, converted)
+  }
+  return resp, nil
+}
+
+func (andyServer) Check(stream grpc.ClientStreamingServer[andypb.Upload, andypb.CheckResponse]) error {
+  master, data, density, err := receiveMaster(stream)
+  if err != nil {
+    return err
+  }
+  config, err := uploadConfig(data)
+  if err != nil {
+    return status.Error(codes.InvalidArgument, err.Error())
+  }
+  report := checkMaster(config, density, master.Name)
+  resp := &andypb.CheckResponse{Checked: int32(report.Checked)}
+  for _, finding := range report.Findings {
+    resp.Findings = append(resp.Findings, &andypb.Finding{Check: finding.Check, Path: finding.Path, Message: finding.Message})
+  }
+  return stream.SendAndClose(resp)
+}
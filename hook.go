@@ -0,0 +1,197 @@
+package main
+
+import (
+  "bytes"
+  "fmt"
+  "log"
+  "os"
+  "os/exec"
+  "path/filepath"
+  "sort"
+  "strings"
+  "github.com/spf13/cobra"
+)
+
+const preCommitHook = `#!/bin/sh
+# Installed by andy hook install: regenerates the densities of staged masters
+# and stops the commit while generated files aren't staged.
+exec %s hook run
+`
+
+var hookAndy string
+
+var hookCmd = &cobra.Command{
+  Use: "hook",
+  Short: "Keep generated assets in sync from a git pre-commit hook.",
+}
+
+var hookInstallCmd = &cobra.Command{
+  Use: "install",
+  Short: "Install the pre-commit hook that runs andy hook run.",
+  Long: `Install the pre-commit hook that runs andy hook run.
+
+An existing pre-commit hook that andy didn't install is left alone unless
+--force is passed.
+
+  andy hook install`,
+  Args: cobra.NoArgs,
+  Run: func(cmd *cobra.Command, args []string) {
+    if err := checkOutputOptions(); err != nil { log.Fatal(err) }
+    hooks, err := git("rev-parse", "--git-path", "hooks")
+    if err != nil { log.Fatal(err) }
+    path := filepath.Join(hooks[0], "pre-commit")
+    if existing, err := os.ReadFile(path); err == nil && !bytes.Contains(existing, []byte("andy hook install")) && !outputOptions.Force {
+      log.Fatalf("%s is another hook, add \"%s hook run\" to it or pass --force to replace it", path, hookAndy)
+    }
+    outputOptions.Force = true
+    if err := writeFile(path, []byte(fmt.Sprintf(preCommitHook, shellQuote(hookAndy)))); err != nil { log.Fatal(err) }
+    if !outputOptions.DryRun {
+      if err := os.Chmod(path, 0755); err != nil { log.Fatal(err) }
+    }
+  },
+}
+
+var hookRunCmd = &cobra.Command{
+  Use: "run",
+  Short: "Regenerate staged masters and fail if generated files aren't staged.",
+  Long: `Regenerate staged masters and fail if generated files aren't staged.
+
+Only the masters in andy.lock that are staged are regenerated, with their
+recorded options, so it stays fast enough to run on every commit. It exits
+nonzero, which stops the commit, when that changed generated files or
+andy.lock without them being staged, or when a staged generated file no
+longer matches andy.lock because it was edited by hand.
+
+  andy hook run`,
+  Args: cobra.NoArgs,
+  Run: func(cmd *cobra.Command, args []string) {
+    if err := checkOutputOptions(); err != nil { log.Fatal(err) }
+    if !fileExists(lockFile) {
+      return
+    }
+    if err := loadLock(); err != nil { log.Fatal(err) }
+    staged, err := stagedFiles()
+    if err != nil { log.Fatal(err) }
+
+    var sources []string
+    outputs := map[string]bool{}
+    for source, asset := range lockedAssets {
+      if staged[source] {
+        sources = append(sources, source)
+      }
+      for output := range asset.Outputs {
+        outputs[output] = true
+      }
+    }
+    sort.Strings(sources)
+    failed := false
+    if len(sources) > 0 {
+      outputOptions.Force = !outputOptions.SkipExisting
+      assets, failures := regenerateLocked(sources)
+      for i, err := range failures {
+        if err != nil {
+          printf("  %s %s: %v\n", red("fail"), assets[i], err)
+          failed = true
+        }
+      }
+    }
+
+    // regenerated files have to go in the commit too
+    paths := []string{lockFile}
+    for _, source := range sources {
+      for output := range lockedAssets[source].Outputs {
+        paths = append(paths, output)
+      }
+    }
+    unstaged, err := unstagedFiles(paths)
+    if err != nil { log.Fatal(err) }
+    for _, path := range unstaged {
+      printf("  %s %s was regenerated, stage it\n", red("fail"), path)
+    }
+    drift := lockDrift()
+    var edited []string
+    for path := range staged {
+      if outputs[path] && drift[path] != "" {
+        edited = append(edited, path)
+      }
+    }
+    sort.Strings(edited)
+    for _, path := range edited {
+      printf("  %s %s %s\n", red("fail"), path, drift[path])
+    }
+    if failed || len(unstaged) > 0 || len(edited) > 0 {
+      printf("%s generated assets aren't in sync, commit stopped\n", red("hook"))
+      os.Exit(1)
+    }
+  },
+}
+
+func init() {
+  addOutputFlags(hookInstallCmd)
+  hookInstallCmd.Flags().StringVar(&hookAndy, "andy", "andy", "andy command the hook runs, if it isn't on the PATH")
+  addOutputFlags(hookRunCmd)
+  hookCmd.AddCommand(hookInstallCmd)
+  hookCmd.AddCommand(hookRunCmd)
+}
+
+// shellQuote quotes s as one word for sh.
+func shellQuote(s string) string {
+  return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// git runs git with args and returns the lines it printed.
+func git(args ...string) ([]string, error) {
+  out, err := exec.Command("git", args...).Output()
+  if err != nil {
+    if exit, ok := err.(*exec.ExitError); ok && len(exit.Stderr) > 0 {
+      return nil, fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exit.Stderr)))
+    }
+    return nil, fmt.Errorf("git %s: %v", args[0], err)
+  }
+  return strings.Split(strings.TrimRight(string(out), "\n"), "\n"), nil
+}
+
+// stagedFiles are the files added or changed in the index, as andy.lock
+// names them.
+func stagedFiles() (map[string]bool, error) {
+  top, err := git("rev-parse", "--show-toplevel")
+  if err != nil {
+    return nil, err
+  }
+  lines, err := git("diff", "--cached", "--name-only", "--diff-filter=ACMR")
+  if err != nil {
+    return nil, err
+  }
+  staged := map[string]bool{}
+  for _, line := range lines {
+    if line != "" {
+      staged[projectPath(filepath.Join(top[0], filepath.FromSlash(line)))] = true
+    }
+  }
+  return staged, nil
+}
+
+// unstagedFiles are the paths whose working copy differs from the index,
+// including ones git doesn't track yet.
+func unstagedFiles(paths []string) ([]string, error) {
+  args := []string{"--"}
+  for _, path := range paths {
+    args = append(args, filepath.FromSlash(path))
+  }
+  changed, err := git(append([]string{"diff", "--name-only", "--relative"}, args...)...)
+  if err != nil {
+    return nil, err
+  }
+  untracked, err := git(append([]string{"ls-files", "--others", "--exclude-standard"}, args...)...)
+  if err != nil {
+    return nil, err
+  }
+  var unstaged []string
+  for _, line := range append(changed, untracked...) {
+    if line != "" {
+      unstaged = append(unstaged, line)
+    }
+  }
+  sort.Strings(unstaged)
+  return unstaged, nil
+}
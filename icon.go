@@ -0,0 +1,211 @@
+package main
+
+import (
+  "fmt"
+  "image"
+  "image/color"
+  "log"
+  "path/filepath"
+  "strings"
+  "github.com/nfnt/resize"
+  "github.com/spf13/cobra"
+)
+
+const (
+  launcherIconDp = 48
+  storeIconSize = 512
+  storeMasterSize = 1024
+)
+
+var (
+  iconName string
+  iconSourceSets []string
+  iconForeground string
+  iconBackground string
+  iconMonochrome string
+  iconMonochromeCutoff float64
+  iconRound bool
+  iconRoundScale float64
+  iconLegacyShape string
+  iconStoreDir string
+  iconStoreMaster bool
+)
+
+var iconCmd = &cobra.Command{
+  Use: "icon [master]",
+  Long: `Generate the launcher icon for every mipmap density plus the Play Store icon from one master.
+
+With --foreground (and optionally --background) an adaptive icon is generated
+too: both layers at 108dp per density and the mipmap-anydpi-v26 wrapper XML.
+The master can be left out, in which case the legacy icons are made from the
+flattened layers. --monochrome adds the Android 13 themed icon layer, from an
+image or "auto" to derive it from the foreground. --round-icon also writes
+<name>_round, masked to a circle.
+
+--legacy-shape renders the legacy icons on a square, squircle or circle
+backdrop (filled with the --background color) with a drop shadow, like
+Android Studio's Image Asset wizard.`,
+  Short: "Generate the launcher icon for every mipmap density plus the Play Store icon from one master.",
+  Run: func(cmd *cobra.Command, args []string) {
+    if len(args) > 1 || (len(args) == 0 && iconForeground == "") {
+      log.Fatal("need one master image, ideally 1024x1024, or --foreground.")
+    }
+    if iconMonochrome != "" && iconForeground == "" {
+      log.Fatal("--monochrome needs a --foreground layer.")
+    }
+    if iconBackground != "" && iconForeground == "" && iconLegacyShape == "" {
+      log.Fatal("--background needs a --foreground layer or --legacy-shape.")
+    }
+    if err := checkOutputOptions(); err != nil {
+      log.Fatal(err)
+    }
+    if !cmd.Flags().Changed("source-set") {
+      iconSourceSets = config.SourceSets
+    }
+    if !cmd.Flags().Changed("store-dir") {
+      iconStoreDir = config.StoreDir
+    }
+
+    var adaptive *AdaptiveIcon
+    if iconForeground != "" {
+      icon, err := loadAdaptiveIcon(iconForeground, iconBackground)
+      if err != nil { log.Fatal(err) }
+      if iconMonochrome != "" {
+        if err := icon.loadMonochrome(iconMonochrome, iconMonochromeCutoff); err != nil {
+          log.Fatal(err)
+        }
+      }
+      adaptive = &icon
+    }
+
+    var master image.Image
+    if len(args) == 1 {
+      var err error
+      master, _, err = decodeImageFile(args[0])
+      if err != nil { log.Fatal(err) }
+      fmt.Printf("%s %s\n", green("from"), args[0])
+    } else {
+      master = adaptive.legacyMaster()
+      fmt.Printf("%s %s\n", green("from"), iconForeground)
+    }
+    if masterOptions.Square {
+      master = padToSquare(master)
+    }
+    checkIconMaster(master)
+    store := master
+    if iconLegacyShape != "" {
+      fill, err := legacyFill(iconBackground)
+      if err != nil { log.Fatal(err) }
+      master, err = renderLegacyIcon(master, iconLegacyShape, fill)
+      if err != nil { log.Fatal(err) }
+    }
+
+    resFolder, err := defaultResFolder()
+    if err != nil { log.Fatal(err) }
+
+    for _, target := range targetResFolders(resFolder, iconSourceSets) {
+      if err := writeLauncherIcons(target, iconName, master); err != nil {
+        log.Fatal(err)
+      }
+      if err := writeStoreIcons(storeFolder(target, iconStoreDir), iconName, store, iconStoreMaster); err != nil {
+        log.Fatal(err)
+      }
+      if iconRound {
+        if err := writeLauncherIcons(target, iconName + "_round", circleMask(store, iconRoundScale)); err != nil {
+          log.Fatal(err)
+        }
+      }
+      if adaptive != nil {
+        if err := writeAdaptiveIcon(target, iconName, adaptive); err != nil {
+          log.Fatal(err)
+        }
+        if iconRound {
+          if err := writeAdaptiveIconXML(target, iconName + "_round", iconName, adaptive); err != nil {
+            log.Fatal(err)
+          }
+        }
+      }
+    }
+  },
+}
+
+func init() {
+  addOutputFlags(iconCmd)
+  addSquareFlag(iconCmd)
+  iconCmd.Flags().StringVar(&iconName, "name", "ic_launcher", "resource name of the launcher icon")
+  iconCmd.Flags().StringVar(&iconForeground, "foreground", "", "adaptive icon foreground layer (108dp canvas)")
+  iconCmd.Flags().StringVar(&iconBackground, "background", "", "adaptive icon background layer, image or #RRGGBB color (default #FFFFFF)")
+  iconCmd.Flags().StringVar(&iconMonochrome, "monochrome", "", "themed icon layer, image or \"auto\" to threshold the foreground")
+  iconCmd.Flags().Float64Var(&iconMonochromeCutoff, "monochrome-threshold", 0.5, "alpha cutoff (0-1) used by --monochrome auto")
+  iconCmd.Flags().BoolVar(&iconRound, "round-icon", false, "also generate the round launcher icon")
+  iconCmd.Flags().Float64Var(&iconRoundScale, "round-scale", 1, "scale of the master inside the round icon's circle, to keep it in the safe zone")
+  iconCmd.Flags().StringVar(&iconLegacyShape, "legacy-shape", "", "render legacy icons on a square, squircle or circle backdrop with a shadow")
+  iconCmd.Flags().StringVar(&iconStoreDir, "store-dir", "", "folder for the Play Store icon (default: next to the res folder)")
+  iconCmd.Flags().BoolVar(&iconStoreMaster, "store-master", false, "also write a 1024x1024 copy of the master into the store folder")
+  iconCmd.Flags().StringSliceVarP(&iconSourceSets, "source-set", "s", nil, "source sets to write generated assets into (e.g. main,paid or all)")
+}
+
+// defaultResFolder is the res folder new assets go to when there's no source
+// drawable to take it from: the first one found, normally src/main/res.
+func defaultResFolder() (string, error) {
+  folders, err := guessResFolders()
+  if err != nil {
+    return "", err
+  }
+  return tryGetAbsPath(folders[0]), nil
+}
+
+// legacyFill is the backdrop color for --legacy-shape: the --background color,
+// or white when the background is an image or not given.
+func legacyFill(background string) (color.Color, error) {
+  if strings.HasPrefix(background, "#") {
+    return parseHexColor(background)
+  }
+  return color.White, nil
+}
+
+func checkIconMaster(master image.Image) {
+  width, height := getDimens(&master)
+  if width != height {
+    fmt.Printf("  %s master is %dx%d, launcher icons should be square\n", yellow("warn"), width, height)
+  }
+  if width < storeIconSize || height < storeIconSize {
+    fmt.Printf("  %s master is %dx%d, smaller than the %dpx Play Store icon\n", yellow("warn"), width, height, storeIconSize)
+  }
+}
+
+func writeMipmaps(resFolder string, filename string, img image.Image, dp float64) error {
+  return writeDensitySet(resFolder, "mipmap", filename, img, dp, dp)
+}
+
+func writeLauncherIcons(resFolder string, name string, master image.Image) error {
+  return writeMipmaps(resFolder, name + ".png", master, launcherIconDp)
+}
+
+// storeFolder is where the Play Store icon goes: storeDir if set, otherwise
+// next to the res folder, where Android Studio's Image Asset wizard puts it.
+func storeFolder(resFolder string, storeDir string) string {
+  if storeDir != "" {
+    return storeDir
+  }
+  return filepath.Dir(resFolder)
+}
+
+func writeStoreIcons(folder string, name string, master image.Image, withMaster bool) error {
+  filenames := []string{name + "-playstore.png"}
+  sizes := []uint{storeIconSize}
+  if withMaster {
+    filenames = append(filenames, name + "-1024.png")
+    sizes = append(sizes, storeMasterSize)
+  }
+  for i, size := range sizes {
+    path := filepath.Join(folder, filenames[i])
+    if skipExisting(path) {
+      continue
+    }
+    if err := writeImage(path, resize.Resize(size, size, master, resize.Lanczos3), "png"); err != nil {
+      return err
+    }
+  }
+  return nil
+}
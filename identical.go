@@ -0,0 +1,44 @@
+package main
+
+import (
+  "bytes"
+  "fmt"
+  "os"
+)
+
+// identicalCopies finds densities of a drawable that are byte for byte the
+// same file as a higher one, a sign it was copied into every bucket instead
+// of exported at each size. Each is returned as a gap to fill again from the
+// highest copy.
+func identicalCopies(resFolders []string) (copies []densityGap, err error) {
+  for _, resFolder := range resFolders {
+    files, err := scanResFiles([]string{resFolder})
+    if err != nil {
+      return nil, err
+    }
+    groups, names := groupResources(files)
+    for _, name := range names {
+      group := groups[name]
+      contents := make([][]byte, len(group))
+      for i, file := range group {
+        if contents[i], err = os.ReadFile(file.Path); err != nil {
+          return nil, err
+        }
+      }
+      for i, file := range group {
+        // the highest density with the same bytes is the source
+        for j := len(group) - 1; j > i; j-- {
+          if group[j].Density != file.Density && bytes.Equal(contents[i], contents[j]) {
+            copies = append(copies, densityGap{Source: group[j], Density: file.Density})
+            break
+          }
+        }
+      }
+    }
+  }
+  return
+}
+
+func copyFinding(copy densityGap) Finding {
+  return Finding{Check: "identical", Path: copy.Path(), Message: fmt.Sprintf("is the same file as %s, copied instead of exported at %s", copy.Source.Path, densityToCanonical[copy.Density])}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+  "bufio"
+  "log"
+  "os"
+  "regexp"
+  "strings"
+  "sync"
+)
+
+// .andyignore in the working directory lists, in gitignore syntax, paths
+// that folder and batch modes leave alone.
+const ignoreFile = ".andyignore"
+
+type ignoreRule struct {
+  pattern *regexp.Regexp
+  negate bool
+  dirOnly bool
+}
+
+var (
+  ignoreOnce sync.Once
+  ignoreRules []ignoreRule
+)
+
+func loadIgnore() {
+  file, err := os.Open(ignoreFile)
+  if err != nil {
+    return
+  }
+  defer file.Close()
+  scanner := bufio.NewScanner(file)
+  for line := 1; scanner.Scan(); line++ {
+    rule, ok, err := parseIgnoreRule(scanner.Text())
+    if err != nil {
+      log.Fatalf("%s:%d: %v", ignoreFile, line, err)
+    }
+    if ok {
+      ignoreRules = append(ignoreRules, rule)
+    }
+  }
+}
+
+func parseIgnoreRule(line string) (rule ignoreRule, ok bool, err error) {
+  line = strings.TrimRight(line, " \t\r")
+  if line == "" || strings.HasPrefix(line, "#") {
+    return
+  }
+  if strings.HasPrefix(line, "!") {
+    rule.negate, line = true, line[1:]
+  } else if strings.HasPrefix(line, "\\") {
+    line = line[1:]
+  }
+  if strings.HasSuffix(line, "/") {
+    rule.dirOnly, line = true, strings.TrimRight(line, "/")
+  }
+  // a pattern with a slash before its end is relative to the root, the rest
+  // match at any depth
+  if strings.Contains(line, "/") {
+    line = strings.TrimPrefix(line, "/")
+  } else {
+    line = "**/" + line
+  }
+  expr, err := globRegex(line)
+  if err != nil {
+    return
+  }
+  rule.pattern, err = regexp.Compile("^" + expr + "$")
+  return rule, err == nil, err
+}
+
+// ignored reports whether .andyignore excludes path, a folder if dir is set.
+// Like git, nothing inside an excluded folder can be included again.
+func ignored(path string, dir bool) bool {
+  ignoreOnce.Do(loadIgnore)
+  if len(ignoreRules) == 0 {
+    return false
+  }
+  rel := projectPath(path)
+  if rel == ".." || strings.HasPrefix(rel, "../") {
+    return false
+  }
+  parts := strings.Split(rel, "/")
+  for i := 1; i < len(parts); i++ {
+    if matchIgnore(strings.Join(parts[:i], "/"), true) {
+      return true
+    }
+  }
+  return matchIgnore(rel, dir)
+}
+
+// matchIgnore applies the rules to rel in order, the last match winning.
+func matchIgnore(rel string, dir bool) (excluded bool) {
+  for _, rule := range ignoreRules {
+    if (!rule.dirOnly || dir) && rule.pattern.MatchString(rel) {
+      excluded = !rule.negate
+    }
+  }
+  return
+}
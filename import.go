@@ -0,0 +1,258 @@
+package main
+
+import (
+  "archive/zip"
+  "errors"
+  "fmt"
+  "io"
+  "io/fs"
+  "log"
+  "os"
+  "path/filepath"
+  "runtime"
+  "strings"
+  "github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+  Use: "import <export zip or folder>",
+  Short: "Bring assets exported by design tools into the res folder.",
+  Long: `Bring assets exported by design tools into the res folder.
+
+Each importer picks the highest resolution export of every asset as its
+source, copies it into the matching density folder under a valid resource
+name, and generates every lower density from it like andy dpi does.
+
+Given a zip from a design handoff, or a folder, andy import unpacks it and
+works out its convention on its own: an Xcode asset catalog, a folder per
+density like drawable-xhdpi or xhdpi, or @2x and @3x suffixes.
+
+  andy import ~/Downloads/handoff.zip`,
+  Args: cobra.ExactArgs(1),
+  Run: func(cmd *cobra.Command, args []string) {
+    if err := checkOutputOptions(); err != nil { log.Fatal(err) }
+    dir := args[0]
+    if !dirExists(dir) {
+      var err error
+      if dir, err = unzipExport(args[0]); err != nil { log.Fatal(err) }
+      defer os.RemoveAll(dir)
+    }
+    images, kind, err := detectImages(dir)
+    if err == nil {
+      fmt.Printf("%s %s is %s\n", green("import"), args[0], kind)
+      err = importImages(images)
+    }
+    if err != nil {
+      // log.Fatal skips the deferred cleanup
+      if dir != args[0] {
+        os.RemoveAll(dir)
+      }
+      log.Fatal(err)
+    }
+  },
+}
+
+func init() {
+  addOutputFlags(importCmd)
+  addMasterFlags(importCmd)
+}
+
+func addImportCommand(cmd *cobra.Command) {
+  addOutputFlags(cmd)
+  addMasterFlags(cmd)
+  importCmd.AddCommand(cmd)
+}
+
+// maxExport is how much an export zip may extract to, each image being at
+// most maxUpload like a master sent to andy serve.
+const maxExport = 1 << 30
+
+// unzipExport extracts the zip at path into a temporary folder, which the
+// caller removes.
+func unzipExport(path string) (string, error) {
+  archive, err := zip.OpenReader(path)
+  if err != nil {
+    return "", fmt.Errorf("%s: %v", path, err)
+  }
+  defer archive.Close()
+  dir, err := os.MkdirTemp("", "andy-import-")
+  if err != nil {
+    return "", err
+  }
+  var total int64
+  tooBig := fmt.Errorf("images can be at most %s and an export %s", formatBytes(maxUpload), formatBytes(maxExport))
+  for _, file := range archive.File {
+    name := filepath.FromSlash(file.Name)
+    // macOS zips carry resource forks, and nothing may land outside dir
+    if file.FileInfo().IsDir() || strings.HasPrefix(name, "__MACOSX") || strings.HasPrefix(filepath.Base(name), ".") || !filepath.IsLocal(name) {
+      continue
+    }
+    // the sizes in the zip can lie, so reading is capped too
+    if file.UncompressedSize64 > maxUpload || total + int64(file.UncompressedSize64) > maxExport {
+      os.RemoveAll(dir)
+      return "", fmt.Errorf("%s: %v", file.Name, tooBig)
+    }
+    reader, err := file.Open()
+    if err != nil {
+      os.RemoveAll(dir)
+      return "", fmt.Errorf("%s: %v", file.Name, err)
+    }
+    data, err := io.ReadAll(io.LimitReader(reader, maxUpload + 1))
+    reader.Close()
+    total += int64(len(data))
+    if err == nil && (len(data) > maxUpload || total > maxExport) {
+      err = tooBig
+    }
+    if err == nil {
+      err = os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0755)
+    }
+    if err == nil {
+      err = os.WriteFile(filepath.Join(dir, name), data, 0644)
+    }
+    if err != nil {
+      os.RemoveAll(dir)
+      return "", fmt.Errorf("%s: %v", file.Name, err)
+    }
+  }
+  return dir, nil
+}
+
+// detectImages lists the images of the export in dir by the first
+// convention it follows: imagesets, density folders or scale suffixes.
+func detectImages(dir string) (images []importedImage, kind string, err error) {
+  var dirs []string
+  imagesets := false
+  err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+    if err != nil || !entry.IsDir() {
+      return err
+    }
+    if filepath.Ext(path) == ".imageset" {
+      imagesets = true
+      return filepath.SkipDir
+    }
+    dirs = append(dirs, path)
+    return nil
+  })
+  if err != nil {
+    return
+  }
+  if imagesets {
+    images, err = catalogImages(dir)
+    return images, "an Xcode asset catalog", err
+  }
+
+  parents := map[string]bool{}
+  for _, path := range dirs {
+    if _, ok := canonicalToDensity(strings.TrimPrefix(filepath.Base(path), "drawable-")); !ok || path == dir || parents[filepath.Dir(path)] {
+      continue
+    }
+    parents[filepath.Dir(path)] = true
+    found, err := zeplinImages(filepath.Dir(path))
+    if err != nil {
+      return nil, "", err
+    }
+    images = append(images, found...)
+  }
+  if len(images) > 0 {
+    return images, "a folder per density", nil
+  }
+
+  for _, path := range dirs {
+    found, err := scaledImages(path)
+    if err != nil {
+      return nil, "", err
+    }
+    images = append(images, found...)
+  }
+  if len(images) == 0 {
+    return nil, "", fmt.Errorf("%s has no images andy knows how to import", dir)
+  }
+  return images, "exported with @2x and @3x suffixes", nil
+}
+
+// importedImage is an asset exported by a design tool, at one density.
+type importedImage struct {
+  Name string
+  Density dpi
+  // Path is the exported file, or Data its contents
+  Path string
+  Data []byte
+  Ext string
+}
+
+// highestImports keeps the highest density of each named image.
+func highestImports(images []importedImage) (highest []importedImage) {
+  index := map[string]int{}
+  for _, image := range images {
+    name := validName(image.Name)
+    if i, ok := index[name]; ok {
+      if image.Density > highest[i].Density {
+        highest[i] = image
+      }
+      continue
+    }
+    index[name] = len(highest)
+    highest = append(highest, image)
+  }
+  return
+}
+
+// importImages writes images into their density folders of the res folder
+// under valid resource names, and generates every lower density from them.
+func importImages(images []importedImage) error {
+  if len(images) == 0 {
+    return errors.New("nothing to import")
+  }
+  resFolders, err := guessResFolders()
+  if err != nil {
+    return err
+  }
+  var assets []string
+  for _, image := range highestImports(images) {
+    folder, ok := densityToFolder[image.Density]
+    if !ok {
+      return fmt.Errorf("%s: no density folder for %s", image.Name, densityToCanonical[image.Density])
+    }
+    data := image.Data
+    if data == nil {
+      if data, err = os.ReadFile(image.Path); err != nil {
+        return err
+      }
+    }
+    if image.Name != validName(image.Name) {
+      printf("  %s %s as %s\n", yellow("rename"), image.Name, validName(image.Name))
+    }
+    target := filepath.Join(resFolders[0], folder, validName(image.Name) + image.Ext)
+    if err := writeFile(target, data); err != nil {
+      return err
+    }
+    assets = append(assets, target)
+  }
+  if outputOptions.DryRun {
+    return nil
+  }
+  if err := loadLock(); err != nil {
+    return err
+  }
+  options := DpiOptions{Rounding: outputOptions.Rounding, Master: masterOptions, Densities: densityValues()}
+  failures := dpitize(assets, options, runtime.NumCPU(), false)
+  failed := 0
+  if len(assets) > 1 {
+    failed = reportSummary(assets, failures)
+  } else if failures[0] != nil {
+    failed = 1
+  }
+  if failed > 0 {
+    return fmt.Errorf("%d of %d assets failed to import", failed, len(assets))
+  }
+  return nil
+}
+
+// scaleDensity is the density of an export at scale, 1x being mdpi.
+func scaleDensity(scale float64) (dpi, error) {
+  density := dpi(scale * MDPI)
+  if _, ok := densityToCanonical[density]; !ok {
+    return 0, fmt.Errorf("no density is %gx", scale)
+  }
+  return density, nil
+}
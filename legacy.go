@@ -0,0 +1,125 @@
+package main
+
+import (
+  "fmt"
+  "image"
+  "image/color"
+  "image/draw"
+  "math"
+  "github.com/nfnt/resize"
+)
+
+const legacyIconDp = 48
+
+// legacyShape describes a pre-O launcher icon backdrop: its size on the 48dp
+// canvas and whether a point (in -1..1 coordinates of its box) is inside it.
+type legacyShape struct {
+  SizeDp float64
+  Contains func(u, v float64) bool
+}
+
+var legacyShapes = map[string]legacyShape{
+  "square": {SizeDp: 38, Contains: roundedSquare(3.0 / 38)},
+  "squircle": {SizeDp: 44, Contains: superellipse(4)},
+  "circle": {SizeDp: 44, Contains: superellipse(2)},
+}
+
+func roundedSquare(radius float64) func(u, v float64) bool {
+  return func(u, v float64) bool {
+    dx := math.Max(0, math.Abs(u)-(1-2*radius))
+    dy := math.Max(0, math.Abs(v)-(1-2*radius))
+    return dx*dx + dy*dy <= 4*radius*radius
+  }
+}
+
+func superellipse(n float64) func(u, v float64) bool {
+  return func(u, v float64) bool {
+    return math.Pow(math.Abs(u), n) + math.Pow(math.Abs(v), n) <= 1
+  }
+}
+
+// renderLegacyIcon draws content on a shape filled with fill, over a soft
+// drop shadow, the way Android Studio's Image Asset wizard renders legacy
+// launcher icons.
+func renderLegacyIcon(content image.Image, shapeName string, fill color.Color) (*image.NRGBA, error) {
+  shape, ok := legacyShapes[shapeName]
+  if !ok {
+    return nil, fmt.Errorf("unknown icon shape %q", shapeName)
+  }
+
+  contentSize, _ := getDimens(&content)
+  dpPx := float64(contentSize) / shape.SizeDp
+  size := int(math.Round(legacyIconDp * dpPx))
+  box := int(math.Round(shape.SizeDp * dpPx))
+  origin := (size - box) / 2
+  mask := shapeMask(shape, size, origin, box)
+
+  canvas := image.NewNRGBA(image.Rect(0, 0, size, size))
+  shadow := blurMask(shiftMask(mask, size, int(math.Round(dpPx))), size, int(math.Max(1, math.Round(dpPx))))
+  for i, coverage := range shadow {
+    canvas.Pix[i*4+3] = uint8(math.Round(coverage * 0.3 * 255))
+  }
+
+  filled := solidImage(box, box, fill)
+  draw.Draw(filled, filled.Bounds(), resize.Resize(uint(box), uint(box), content, resize.Lanczos3), image.Point{}, draw.Over)
+  clipped := image.NewNRGBA(image.Rect(0, 0, size, size))
+  draw.Draw(clipped, image.Rect(origin, origin, origin+box, origin+box), filled, image.Point{}, draw.Src)
+  for i, coverage := range mask {
+    clipped.Pix[i*4+3] = uint8(math.Round(float64(clipped.Pix[i*4+3]) * coverage))
+  }
+  draw.Draw(canvas, canvas.Bounds(), clipped, image.Point{}, draw.Over)
+  return canvas, nil
+}
+
+// shapeMask returns the antialiased coverage of shape for every pixel of a
+// size x size canvas, with the shape's box at origin.
+func shapeMask(shape legacyShape, size int, origin int, box int) []float64 {
+  const samples = 4
+  mask := make([]float64, size*size)
+  for y := origin; y < origin+box; y++ {
+    for x := origin; x < origin+box; x++ {
+      hits := 0
+      for sy := 0; sy < samples; sy++ {
+        for sx := 0; sx < samples; sx++ {
+          u := (float64(x-origin)+(float64(sx)+0.5)/samples)/float64(box)*2 - 1
+          v := (float64(y-origin)+(float64(sy)+0.5)/samples)/float64(box)*2 - 1
+          if shape.Contains(u, v) {
+            hits++
+          }
+        }
+      }
+      mask[y*size+x] = float64(hits) / (samples * samples)
+    }
+  }
+  return mask
+}
+
+func shiftMask(mask []float64, size int, dy int) []float64 {
+  shifted := make([]float64, len(mask))
+  for y := dy; y < size; y++ {
+    copy(shifted[y*size:(y+1)*size], mask[(y-dy)*size:(y-dy+1)*size])
+  }
+  return shifted
+}
+
+// blurMask applies a box blur of the given radius horizontally then vertically.
+func blurMask(mask []float64, size int, radius int) []float64 {
+  pass := func(src []float64, at func(i, j int) int) []float64 {
+    out := make([]float64, len(src))
+    for i := 0; i < size; i++ {
+      for j := 0; j < size; j++ {
+        sum, count := 0.0, 0
+        for k := j - radius; k <= j+radius; k++ {
+          if k >= 0 && k < size {
+            sum += src[at(i, k)]
+            count++
+          }
+        }
+        out[at(i, j)] = sum / float64(count)
+      }
+    }
+    return out
+  }
+  horizontal := pass(mask, func(row, col int) int { return row*size + col })
+  return pass(horizontal, func(col, row int) int { return row*size + col })
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+  "bytes"
+  "crypto/sha256"
+  "encoding/hex"
+  "fmt"
+  "os"
+  "path/filepath"
+  "reflect"
+  "sort"
+  "sync"
+  "github.com/BurntSushi/toml"
+)
+
+// andy.lock records every source andy dpi generated from, relative to the
+// working directory, so the generated assets can be rebuilt with andy regen.
+const lockFile = "andy.lock"
+
+type Lock struct {
+  Assets []LockedAsset `toml:"asset"`
+}
+
+// LockedAsset is what a source last generated: its hash, the options it was
+// generated with and the hash of every file written.
+type LockedAsset struct {
+  Source string `toml:"source"`
+  Hash string `toml:"hash"`
+  Options DpiOptions `toml:"options"`
+  Outputs map[string]string `toml:"outputs"`
+}
+
+var (
+  lockMutex sync.Mutex
+  lockedAssets map[string]LockedAsset
+)
+
+func dataHash(data []byte) string {
+  sum := sha256.Sum256(data)
+  return hex.EncodeToString(sum[:])
+}
+
+func fileHash(path string) (string, error) {
+  data, err := os.ReadFile(path)
+  if err != nil {
+    return "", err
+  }
+  return dataHash(data), nil
+}
+
+// projectPath is path relative to the working directory, with forward
+// slashes, as the lock stores it.
+func projectPath(path string) string {
+  wd, err := os.Getwd()
+  if err != nil {
+    return filepath.ToSlash(path)
+  }
+  rel, err := filepath.Rel(wd, tryGetAbsPath(path))
+  if err != nil {
+    return filepath.ToSlash(path)
+  }
+  return filepath.ToSlash(rel)
+}
+
+func loadLock() error {
+  lockedAssets = map[string]LockedAsset{}
+  if !fileExists(lockFile) {
+    return nil
+  }
+  var lock Lock
+  if _, err := toml.DecodeFile(lockFile, &lock); err != nil {
+    return fmt.Errorf("%s: %v", lockFile, err)
+  }
+  for _, asset := range lock.Assets {
+    lockedAssets[asset.Source] = asset
+  }
+  return nil
+}
+
+func saveLock() error {
+  var lock Lock
+  for _, asset := range lockedAssets {
+    lock.Assets = append(lock.Assets, asset)
+  }
+  sort.Slice(lock.Assets, func(i, j int) bool { return lock.Assets[i].Source < lock.Assets[j].Source })
+  var buf bytes.Buffer
+  buf.WriteString("# Generated by andy, rebuild the assets in it with andy regen.\n\n")
+  if err := toml.NewEncoder(&buf).Encode(lock); err != nil {
+    return err
+  }
+  // the lock goes back with andy undo, so it's always backed up
+  existing, err := os.ReadFile(lockFile)
+  entry := JournalEntry{Path: lockFile, Created: err != nil}
+  if err == nil {
+    if bytes.Equal(existing, buf.Bytes()) {
+      return nil
+    }
+    if entry.Backup, err = backupFile(lockFile, existing); err != nil {
+      return err
+    }
+  }
+  if err := writeAtomic(lockFile, buf.Bytes()); err != nil {
+    return err
+  }
+  return journalFile(entry)
+}
+
+// upToDate reports whether source, at hash, was already generated with
+// options and all of its outputs are still as andy wrote them.
+func upToDate(source string, hash string, options DpiOptions) bool {
+  lockMutex.Lock()
+  asset, ok := lockedAssets[projectPath(source)]
+  lockMutex.Unlock()
+  if !ok || asset.Hash != hash || !reflect.DeepEqual(asset.Options, options) || len(asset.Outputs) == 0 {
+    return false
+  }
+  for path, outputHash := range asset.Outputs {
+    if current, err := fileHash(filepath.FromSlash(path)); err != nil || current != outputHash {
+      return false
+    }
+  }
+  return true
+}
+
+func recordBuild(source string, hash string, options DpiOptions, outputs map[string]string) {
+  lockMutex.Lock()
+  defer lockMutex.Unlock()
+  asset := LockedAsset{Source: projectPath(source), Hash: hash, Options: options, Outputs: map[string]string{}}
+  for path, outputHash := range outputs {
+    asset.Outputs[projectPath(path)] = outputHash
+  }
+  lockedAssets[asset.Source] = asset
+}
+
+// lockDrift maps each locked source or output that no longer matches the
+// lock to what changed.
+func lockDrift() map[string]string {
+  drift := map[string]string{}
+  for _, asset := range lockedAssets {
+    hash, err := fileHash(filepath.FromSlash(asset.Source))
+    if err != nil {
+      drift[asset.Source] = "is missing, its outputs can't be regenerated"
+    } else if hash != asset.Hash {
+      drift[asset.Source] = "changed since its outputs were generated, run andy regen"
+    }
+    for path, outputHash := range asset.Outputs {
+      current, err := fileHash(filepath.FromSlash(path))
+      if err != nil {
+        drift[path] = "is missing, run andy regen"
+      } else if current != outputHash {
+        drift[path] = fmt.Sprintf("differs from what andy generated from %s", asset.Source)
+      }
+    }
+  }
+  return drift
+}
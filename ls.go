@@ -0,0 +1,129 @@
+package main
+
+import (
+  "fmt"
+  "log"
+  "os"
+  "strings"
+  "text/tabwriter"
+  "github.com/spf13/cobra"
+)
+
+var lsBytes bool
+
+var lsCmd = &cobra.Command{
+  Use: "ls [res folders]",
+  Short: "List every drawable with the density buckets it has.",
+  Long: `List every drawable with the density buckets it has.
+
+Each row is a drawable (qualified variants like night get their own) with
+its size in pixels in every bucket, - where it's missing, and the bytes it
+takes in total. --bytes shows the file size of each bucket instead.
+--output json prints every drawable with its files for scripts.
+
+  andy ls
+  andy ls app/src/main/res --bytes
+  andy ls --output json`,
+  Run: func(cmd *cobra.Command, args []string) {
+    if len(args) > 0 {
+      resDirs = args
+    }
+    if err := checkReportFormat(cmd); err != nil { log.Fatal(err) }
+    resFolders, err := guessResFolders()
+    if err != nil { log.Fatal(err) }
+    if reportFormat == "json" {
+      files, err := scanResFiles(resFolders)
+      if err != nil { log.Fatal(err) }
+      if err := printCoverageJSON(files); err != nil { log.Fatal(err) }
+      return
+    }
+    for i, resFolder := range resFolders {
+      files, err := scanResFiles([]string{resFolder})
+      if err != nil { log.Fatal(err) }
+      if i > 0 {
+        fmt.Println()
+      }
+      fmt.Printf("%s %s\n", green("res"), resFolder)
+      if err := printCoverage(files); err != nil { log.Fatal(err) }
+    }
+  },
+}
+
+func init() {
+  lsCmd.Flags().BoolVar(&lsBytes, "bytes", false, "show the file size in each bucket instead of the pixel size")
+  addReportFlags(lsCmd, "table", "json")
+}
+
+type drawableJSON struct {
+  ResFolder string `json:"res_folder"`
+  Type string `json:"type"`
+  Name string `json:"name"`
+  Qualifiers string `json:"qualifiers"`
+  Bytes int64 `json:"bytes"`
+  Files []fileJSON `json:"files"`
+}
+
+// printCoverageJSON prints every drawable with its files, lowest density
+// first.
+func printCoverageJSON(files []resFile) error {
+  drawables := []drawableJSON{}
+  byFolder := map[string][]resFile{}
+  var resFolders []string
+  for _, file := range files {
+    if _, ok := byFolder[file.ResFolder]; !ok {
+      resFolders = append(resFolders, file.ResFolder)
+    }
+    byFolder[file.ResFolder] = append(byFolder[file.ResFolder], file)
+  }
+  for _, resFolder := range resFolders {
+    groups, names := groupResources(byFolder[resFolder])
+    for _, name := range names {
+      first := groups[name][0]
+      drawable := drawableJSON{ResFolder: resFolder, Type: first.Type, Name: first.Name, Qualifiers: first.Qualifiers}
+      for _, file := range groups[name] {
+        drawable.Bytes += file.Size
+        drawable.Files = append(drawable.Files, file.JSON())
+      }
+      drawables = append(drawables, drawable)
+    }
+  }
+  return printJSON(drawables)
+}
+
+// printCoverage prints the matrix of drawables by density bucket.
+func printCoverage(files []resFile) error {
+  groups, names := groupResources(files)
+  header := []string{"drawable"}
+  for _, density := range ascendingDensityList {
+    header = append(header, densityToCanonical[density])
+  }
+  rows := [][]string{append(header, "total")}
+  for _, name := range names {
+    row := []string{name}
+    var total int64
+    for _, density := range ascendingDensityList {
+      cell := "-"
+      for _, file := range groups[name] {
+        if file.Density != density {
+          continue
+        }
+        total += file.Size
+        if lsBytes {
+          cell = formatBytes(file.Size)
+        } else if dimens, err := file.Dimens(); err == nil {
+          cell = fmt.Sprintf("%dx%d", dimens.X, dimens.Y)
+        } else {
+          cell = "?"
+        }
+      }
+      row = append(row, cell)
+    }
+    rows = append(rows, append(row, formatBytes(total)))
+  }
+
+  writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+  for _, row := range rows {
+    fmt.Fprintln(writer, strings.Join(row, "\t") + "\t")
+  }
+  return writer.Flush()
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+  "fmt"
+  "image"
+  "image/draw"
+  "strconv"
+  "strings"
+  "github.com/spf13/cobra"
+)
+
+// MasterOptions are transforms applied to a source asset before its densities
+// are generated.
+type MasterOptions struct {
+  Crop string `toml:"crop,omitempty"`
+  Trim string `toml:"trim,omitempty"`
+  Square bool `toml:"square,omitempty"`
+  Rotate int `toml:"rotate,omitzero"`
+  Flip string `toml:"flip,omitempty"`
+}
+
+var masterOptions MasterOptions
+
+func addMasterFlags(cmd *cobra.Command) {
+  cmd.Flags().StringVar(&masterOptions.Trim, "trim", "", "crop transparent borders and re-add this much padding in dp (--trim=4)")
+  cmd.Flags().Lookup("trim").NoOptDefVal = "0"
+  addSquareFlag(cmd)
+  cmd.Flags().IntVar(&masterOptions.Rotate, "rotate", 0, "rotate the master clockwise by 90, 180 or 270 degrees")
+  cmd.Flags().StringVar(&masterOptions.Flip, "flip", "", "flip the master horizontally (h) or vertically (v)")
+}
+
+func addSquareFlag(cmd *cobra.Command) {
+  cmd.Flags().BoolVar(&masterOptions.Square, "square", false, "pad the master to a centered square canvas first")
+}
+
+// transformsMaster reports whether the master is changed before resizing, in
+// which case the transformed master is written back to its own density too.
+func transformsMaster() bool {
+  return masterOptions.Crop != "" || masterOptions.Trim != "" || masterOptions.Square || masterOptions.Rotate != 0 || masterOptions.Flip != ""
+}
+
+func prepareMaster(img image.Image, density dpi) (image.Image, error) {
+  if masterOptions.Crop != "" {
+    rect, err := parseDpRect(masterOptions.Crop, density)
+    if err != nil {
+      return nil, err
+    }
+    if !rect.Add(img.Bounds().Min).In(img.Bounds()) {
+      return nil, fmt.Errorf("crop %s is outside the %dx%dpx image", masterOptions.Crop, img.Bounds().Dx(), img.Bounds().Dy())
+    }
+    cropped := image.NewNRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+    draw.Draw(cropped, cropped.Rect, img, rect.Min.Add(img.Bounds().Min), draw.Src)
+    img = cropped
+  }
+  switch masterOptions.Rotate {
+  case 0:
+  case 90, 180, 270:
+    for i := 0; i < masterOptions.Rotate / 90; i++ {
+      img = rotateClockwise(img)
+    }
+  default:
+    return nil, fmt.Errorf("invalid --rotate %d, expected 90, 180 or 270", masterOptions.Rotate)
+  }
+  switch masterOptions.Flip {
+  case "":
+  case "h":
+    img = flipHorizontal(img)
+  case "v":
+    img = flipVertical(img)
+  default:
+    return nil, fmt.Errorf("invalid --flip %q, expected h or v", masterOptions.Flip)
+  }
+  if masterOptions.Trim != "" {
+    padding, err := strconv.ParseFloat(masterOptions.Trim, 64)
+    if err != nil || padding < 0 {
+      return nil, fmt.Errorf("invalid --trim padding %q", masterOptions.Trim)
+    }
+    img = trimTransparent(img, dpToPx(padding, density))
+  }
+  if masterOptions.Square {
+    img = padToSquare(img)
+  }
+  return img, nil
+}
+
+// padToSquare centers img on a transparent square canvas as big as its
+// longest side.
+func padToSquare(img image.Image) image.Image {
+  width, height := getDimens(&img)
+  if width == height {
+    return img
+  }
+  size := width
+  if height > size {
+    size = height
+  }
+  square := image.NewNRGBA(image.Rect(0, 0, size, size))
+  offset := image.Pt((size-width)/2, (size-height)/2)
+  draw.Draw(square, image.Rect(0, 0, width, height).Add(offset), img, img.Bounds().Min, draw.Src)
+  return square
+}
+
+// trimTransparent crops fully transparent rows and columns from the edges of
+// img and adds padding transparent pixels back on every side.
+func trimTransparent(img image.Image, padding int) image.Image {
+  src := toNRGBA(img)
+  bounds := image.Rectangle{}
+  for y := 0; y < src.Rect.Dy(); y++ {
+    for x := 0; x < src.Rect.Dx(); x++ {
+      if src.NRGBAAt(x, y).A > 0 {
+        bounds = bounds.Union(image.Rect(x, y, x+1, y+1))
+      }
+    }
+  }
+  if bounds.Empty() {
+    return img
+  }
+
+  trimmed := image.NewNRGBA(image.Rect(0, 0, bounds.Dx()+2*padding, bounds.Dy()+2*padding))
+  draw.Draw(trimmed, bounds.Sub(bounds.Min).Add(image.Pt(padding, padding)), src, bounds.Min, draw.Src)
+  return trimmed
+}
+
+// parseDpRect parses x,y,width,height in dp (4dp,4dp,40dp,40dp) into a pixel
+// rectangle at density.
+func parseDpRect(s string, density dpi) (rect image.Rectangle, err error) {
+  parts := strings.Split(s, ",")
+  if len(parts) != 4 {
+    return rect, fmt.Errorf("invalid rect %q, expected x,y,width,height in dp", s)
+  }
+  var px [4]int
+  for i, part := range parts {
+    value, parseErr := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(part), "dp"), 64)
+    if parseErr != nil || value < 0 {
+      return rect, fmt.Errorf("invalid rect %q, expected x,y,width,height in dp", s)
+    }
+    px[i] = dpToPx(value, density)
+  }
+  rect = image.Rect(px[0], px[1], px[0]+px[2], px[1]+px[3])
+  if rect.Empty() {
+    return rect, fmt.Errorf("rect %q is empty", s)
+  }
+  return
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+  "bytes"
+  "fmt"
+  "log"
+  "os"
+  "path/filepath"
+  "github.com/spf13/cobra"
+)
+
+var (
+  mergeConflicts string
+  mergeSuffix string
+)
+
+var mergeCmd = &cobra.Command{
+  Use: "merge <incoming res folder> <res folder>",
+  Short: "Fold a drop of exported assets into a res folder.",
+  Long: `Fold a drop of exported assets into a res folder.
+
+Every file in the density folders of the incoming folder is copied to the
+same folder of the res folder. Files that are already there and differ are
+conflicts, settled by --conflicts:
+
+  prompt     ask about each one (--force and --skip-existing answer for you)
+  newer      take whichever file was modified last
+  keep-both  keep the existing file and add the incoming one with --suffix
+
+Then the densities the merged drawables are missing below their highest one
+are generated from it. andy undo reverses the whole merge.
+
+  andy merge ~/Downloads/drop/res app/src/main/res
+  andy merge drop/res app/src/main/res --conflicts keep-both --suffix _v2`,
+  Args: cobra.ExactArgs(2),
+  Run: func(cmd *cobra.Command, args []string) {
+    incoming, resFolder := args[0], args[1]
+    switch mergeConflicts {
+    case "prompt", "newer", "keep-both":
+    default:
+      log.Fatalf("unknown conflict policy %q, use prompt, newer or keep-both", mergeConflicts)
+    }
+    if err := checkOutputOptions(); err != nil { log.Fatal(err) }
+    for _, dir := range args {
+      if !dirExists(dir) {
+        log.Fatalf("res folder %s not found", dir)
+      }
+    }
+    files, err := scanResFiles([]string{incoming})
+    if err != nil { log.Fatal(err) }
+
+    merged := map[string]bool{}
+    same := 0
+    for _, file := range files {
+      target := filepath.Join(resFolder, filepath.Base(filepath.Dir(file.Path)), filepath.Base(file.Path))
+      data, err := os.ReadFile(file.Path)
+      if err != nil { log.Fatal(err) }
+      existing, err := os.ReadFile(target)
+      name := file.Name
+      switch {
+      case err != nil:
+        err = writeFile(target, data)
+      case bytes.Equal(existing, data):
+        same++
+        continue
+      case mergeConflicts == "newer":
+        var newer bool
+        if newer, err = modifiedAfter(file.Path, target); err == nil && newer {
+          err = replaceFile(target, data)
+        } else if err == nil {
+          printf("  %s %s is newer\n", yellow("skip"), target)
+        }
+      case mergeConflicts == "keep-both":
+        renamed := file
+        renamed.Path = target
+        name += mergeSuffix
+        err = writeFile(renamed.Renamed(name), data)
+      default:
+        err = writeFile(target, data)
+      }
+      if err != nil { log.Fatal(err) }
+      merged[name] = true
+    }
+
+    gaps, err := missingDensities([]string{resFolder})
+    if err != nil { log.Fatal(err) }
+    var fill []densityGap
+    for _, gap := range gaps {
+      if merged[gap.Source.Name] {
+        fill = append(fill, gap)
+      }
+    }
+    if err := fillMissing(fill); err != nil { log.Fatal(err) }
+    fmt.Printf("%s %d new or changed files from %s, %d already the same, %d densities generated\n", green("merge"), len(files) - same, incoming, same, len(fill))
+  },
+}
+
+func init() {
+  addOutputFlags(mergeCmd)
+  mergeCmd.Flags().StringVar(&mergeConflicts, "conflicts", "prompt", "how to settle files that exist and differ: prompt, newer or keep-both")
+  mergeCmd.Flags().StringVar(&mergeSuffix, "suffix", "_incoming", "name suffix for incoming files kept next to existing ones")
+}
+
+// modifiedAfter reports whether the file at path was modified after the one
+// at other.
+func modifiedAfter(path string, other string) (bool, error) {
+  info, err := os.Stat(path)
+  if err != nil {
+    return false, err
+  }
+  otherInfo, err := os.Stat(other)
+  if err != nil {
+    return false, err
+  }
+  return info.ModTime().After(otherInfo.ModTime()), nil
+}
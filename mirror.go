@@ -0,0 +1,57 @@
+package main
+
+import (
+  "image"
+  "log"
+  "github.com/spf13/cobra"
+)
+
+const rtlQualifier = "ldrtl"
+
+var (
+  mirrorSourceSets []string
+  mirrorPreserveFormat bool
+)
+
+var mirrorCmd = &cobra.Command{
+  Use: "mirror [assets]",
+  Short: "Generate horizontally-flipped drawable-ldrtl-* copies of directional assets for every density.",
+  Run: func(cmd *cobra.Command, args []string) {
+    if len(args) < 1 {
+      log.Fatal("need one or more filenames.")
+    }
+    if err := checkOutputOptions(); err != nil {
+      log.Fatal(err)
+    }
+    if !cmd.Flags().Changed("source-set") {
+      mirrorSourceSets = config.SourceSets
+    }
+    for _, arg := range args {
+      drawableInfo, img, err := openDrawable(arg)
+      if err != nil { log.Fatal(err) }
+      if !mirrorPreserveFormat {
+        drawableInfo.Format = "png"
+      }
+
+      for _, resFolder := range targetResFolders(drawableInfo.ResFolder, mirrorSourceSets) {
+        target := drawableInfo
+        target.ResFolder = resFolder
+        if err := mirrorToFolders(&target, &img); err != nil {
+          log.Fatal(err)
+        }
+      }
+    }
+  },
+}
+
+func init() {
+  addOutputFlags(mirrorCmd)
+  addMasterFlags(mirrorCmd)
+  mirrorCmd.Flags().StringSliceVarP(&mirrorSourceSets, "source-set", "s", nil, "source sets to write generated assets into (e.g. main,paid or all)")
+  mirrorCmd.Flags().BoolVar(&mirrorPreserveFormat, "preserve-format", false, "write generated assets in the source's format (jpeg, webp) instead of png")
+}
+
+func mirrorToFolders(drawableInfo *DrawableInfo, img *image.Image) error {
+  mirrored := flipHorizontal(*img)
+  return variantToFolders(drawableInfo, &mirrored, rtlQualifier)
+}
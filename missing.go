@@ -0,0 +1,103 @@
+package main
+
+import (
+  "fmt"
+  "math"
+  "path/filepath"
+  "strings"
+  "github.com/nfnt/resize"
+)
+
+// densityGap is a bucket a drawable is missing below its highest density.
+type densityGap struct {
+  Source resFile
+  Density dpi
+}
+
+// Path is where the missing file goes, the source's folder with the gap's
+// density qualifier, e.g. drawable-night-xhdpi for drawable-night-xxhdpi.
+func (gap densityGap) Path() string {
+  folder := filepath.Base(filepath.Dir(gap.Source.Path))
+  parts := strings.Split(folder, "-")
+  for i, part := range parts {
+    if part == densityToCanonical[gap.Source.Density] {
+      parts[i] = densityToCanonical[gap.Density]
+    }
+  }
+  if target, ok := densityToFolder[gap.Density]; ok && folder == densityToFolder[gap.Source.Density] {
+    // custom density folders from the config don't follow the pattern
+    parts = []string{target}
+  }
+  return filepath.Join(gap.Source.ResFolder, strings.Join(parts, "-"), filepath.Base(gap.Source.Path))
+}
+
+func (gap densityGap) Finding() Finding {
+  return Finding{Check: "missing", Path: gap.Path(), Message: fmt.Sprintf("is missing, %s has %s", gap.Source.Resource(), densityToCanonical[gap.Source.Density])}
+}
+
+// missingDensities finds the buckets each drawable doesn't have below its
+// highest density, leaving out the ones its sidecar skips.
+func missingDensities(resFolders []string) (gaps []densityGap, err error) {
+  for _, resFolder := range resFolders {
+    files, err := scanResFiles([]string{resFolder})
+    if err != nil {
+      return nil, err
+    }
+    groups, names := groupResources(files)
+    for _, name := range names {
+      group := groups[name]
+      source := group[len(group) - 1]
+      asset, err := assetConfig(source.Path)
+      if err != nil {
+        return nil, err
+      }
+      skip, err := asset.skipDensities()
+      if err != nil {
+        return nil, err
+      }
+      has := map[dpi]bool{}
+      for _, file := range group {
+        has[file.Density] = true
+      }
+      for _, density := range ascendingDensityList {
+        if density < source.Density && !has[density] && !skip[density] {
+          gaps = append(gaps, densityGap{Source: source, Density: density})
+        }
+      }
+    }
+  }
+  return
+}
+
+// fillMissing writes each gap resized from its drawable's highest density.
+func fillMissing(gaps []densityGap) error {
+  for _, gap := range gaps {
+    img, format, err := decodeImageFile(gap.Source.Path)
+    if err != nil {
+      return err
+    }
+    if format != "jpeg" && format != "webp" {
+      format = "png"
+    }
+    width, height := getDimens(&img)
+    ninePatch := isNinePatch(filepath.Base(gap.Source.Path))
+    if ninePatch {
+      width, height = width - 2, height - 2
+    }
+    printf("%s %s\n", green("from"), gap.Source.Path)
+    targetWidth, exactWidth := scaleDimension(width, gap.Source.Density, gap.Density)
+    targetHeight, exactHeight := scaleDimension(height, gap.Source.Density, gap.Density)
+    if math.Abs(float64(targetWidth)-exactWidth) > outputOptions.RoundingWarn || math.Abs(float64(targetHeight)-exactHeight) > outputOptions.RoundingWarn {
+      printf("  %s %s is %.2fx%.2fpx, rounded to %dx%d\n", yellow("warn"), gap.Path(), exactWidth, exactHeight, targetWidth, targetHeight)
+    }
+    if ninePatch {
+      err = writeImage(gap.Path(), resizeNinePatch(img, targetWidth, targetHeight), "png")
+    } else {
+      err = writeImage(gap.Path(), resize.Resize(uint(targetWidth), uint(targetHeight), img, resizeFilter()), format)
+    }
+    if err != nil {
+      return err
+    }
+  }
+  return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+  "fmt"
+  "log"
+  "regexp"
+  "github.com/spf13/cobra"
+)
+
+var mvRefs bool
+
+var mvCmd = &cobra.Command{
+  Use: "mv <name> <new name>",
+  Short: "Rename a drawable in every density folder.",
+  Long: `Rename a drawable in every density folder.
+
+Every density and qualifier folder of every res folder is searched for the
+drawable, and each of its files is renamed, keeping its extension and
+nine-patch suffix. With --refs, @drawable/name and R.drawable.name references
+in the xml, Kotlin and Java files under the working directory are rewritten
+to the new name too. andy undo moves everything back.
+
+  andy mv ic_foo ic_bar
+  andy mv ic_foo ic_bar --refs`,
+  Args: cobra.ExactArgs(2),
+  Run: func(cmd *cobra.Command, args []string) {
+    name, newName := args[0], args[1]
+    if !fileResourceRegex.MatchString(newName) {
+      log.Fatalf("%s isn't a valid resource name, use lowercase letters, digits and underscores.", newName)
+    }
+    resFolders, err := guessResFolders()
+    if err != nil { log.Fatal(err) }
+    files, err := scanResFiles(resFolders)
+    if err != nil { log.Fatal(err) }
+
+    named := filesNamed(files, name)
+    if len(named) == 0 {
+      log.Fatalf("no drawable named %s in %v", name, resFolders)
+    }
+    types := map[string]bool{}
+    for _, file := range named {
+      if existing := filesNamed(files, file.Type + "/" + newName); len(existing) > 0 {
+        log.Fatalf("%s already exists", existing[0].Path)
+      }
+      types[file.Type] = true
+    }
+    for _, file := range named {
+      if err := moveFile(file.Path, file.Renamed(newName)); err != nil { log.Fatal(err) }
+    }
+
+    rewritten := 0
+    if mvRefs {
+      for resType := range types {
+        count, err := rewriteReferences(".", resType, named[0].Name, newName)
+        if err != nil { log.Fatal(err) }
+        rewritten += count
+      }
+    }
+    fmt.Printf("%s %d files", green("mv"), len(named))
+    if mvRefs {
+      fmt.Printf(", %d sources rewritten", rewritten)
+    }
+    fmt.Println()
+  },
+}
+
+func init() {
+  mvCmd.Flags().BoolVar(&mvRefs, "refs", false, "also rewrite references in xml, Kotlin and Java sources")
+}
+
+// fileResourceRegex matches the names aapt accepts for file based resources.
+var fileResourceRegex = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// rewriteReferences renames references to resType/name in the sources under
+// root, returning how many files changed.
+func rewriteReferences(root string, resType string, name string, newName string) (count int, err error) {
+  ref := regexp.MustCompile(`(@|\bR\.)` + resType + `([/.])` + regexp.QuoteMeta(name) + `\b`)
+  err = walkSources(root, func(path string, data []byte) error {
+    rewritten := ref.ReplaceAll(data, []byte("${1}" + resType + "${2}" + newName))
+    if string(rewritten) == string(data) {
+      return nil
+    }
+    count++
+    return replaceFile(path, rewritten)
+  })
+  return
+}
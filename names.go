@@ -0,0 +1,85 @@
+package main
+
+import (
+  "fmt"
+  "path/filepath"
+  "regexp"
+  "strings"
+  "unicode"
+)
+
+// checkNames flags files aapt will refuse: resource names need to be
+// lowercase letters, digits and underscores, not starting with a digit, and
+// the extension has to be lowercase too.
+func checkNames(resFolders []string) (findings []Finding, err error) {
+  files, err := scanResFiles(resFolders)
+  if err != nil {
+    return nil, err
+  }
+  for _, file := range files {
+    if problems := nameProblems(file); len(problems) > 0 {
+      findings = append(findings, Finding{Check: "names", Path: file.Path, Message: "isn't a valid resource name, it " + strings.Join(problems, ", ")})
+    }
+  }
+  return
+}
+
+// strayCharRegex matches what's left once case and dashes are dealt with.
+var strayCharRegex = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// fixNames renames every file with an invalid name to its valid name, which
+// is the same in each density folder so the variants stay together.
+func fixNames(resFolders []string) error {
+  files, err := scanResFiles(resFolders)
+  if err != nil {
+    return err
+  }
+  for _, file := range files {
+    if len(nameProblems(file)) == 0 {
+      continue
+    }
+    suffix := strings.ToLower(strings.TrimPrefix(filepath.Base(file.Path), file.Name))
+    target := filepath.Join(filepath.Dir(file.Path), validName(file.Name) + suffix)
+    if err := moveFile(file.Path, target); err != nil {
+      printf("  %s %s can't be renamed: %v\n", yellow("skip"), file.Path, err)
+    }
+  }
+  return nil
+}
+
+var (
+  camelRegex = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+  underscoresRegex = regexp.MustCompile(`_+`)
+)
+
+// validName turns name into a lowercase_underscore resource name, e.g.
+// Ic-SendButton becomes ic_send_button and Icons / Send becomes icons_send.
+func validName(name string) string {
+  name = camelRegex.ReplaceAllString(name, "${1}_${2}")
+  name = strayCharRegex.ReplaceAllString(strings.ReplaceAll(name, "-", "_"), "_")
+  name = strings.Trim(underscoresRegex.ReplaceAllString(strings.ToLower(name), "_"), "_")
+  if name == "" || unicode.IsDigit(rune(name[0])) {
+    name = "_" + name
+  }
+  return name
+}
+
+func nameProblems(file resFile) (problems []string) {
+  name := file.Name
+  if strings.ToLower(name) != name {
+    problems = append(problems, "has uppercase letters")
+  }
+  if strings.Contains(name, "-") {
+    problems = append(problems, "has dashes")
+  }
+  if name != "" && unicode.IsDigit(rune(name[0])) {
+    problems = append(problems, "starts with a digit")
+  }
+  if strayCharRegex.MatchString(name) {
+    problems = append(problems, "has characters other than letters, digits and underscores")
+  }
+  if ext := filepath.Ext(file.Path); strings.ToLower(ext) != ext {
+    problems = append(problems, fmt.Sprintf("ends in %s instead of %s", ext, strings.ToLower(ext)))
+  }
+  return
+}
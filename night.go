@@ -0,0 +1,43 @@
+package main
+
+import (
+  "fmt"
+  "image"
+  "strconv"
+  "strings"
+)
+
+const nightQualifier = "night"
+
+func nightVariant(img image.Image, source string, toNight transform) (image.Image, error) {
+  if source != "" {
+    nightImg, _, err := decodeImageFile(source)
+    return nightImg, err
+  }
+  return toNight(img), nil
+}
+
+func parseNightTransform(spec string) (transform, error) {
+  name, arg := spec, ""
+  if i := strings.Index(spec, ":"); i >= 0 {
+    name, arg = spec[:i], spec[i+1:]
+  }
+
+  switch name {
+  case "invert":
+    return invert, nil
+  case "tint":
+    c, err := parseHexColor(arg)
+    if err != nil {
+      return nil, err
+    }
+    return func(img image.Image) image.Image { return tint(img, c) }, nil
+  case "brightness":
+    factor, err := strconv.ParseFloat(arg, 64)
+    if err != nil || factor < 0 {
+      return nil, fmt.Errorf("invalid brightness factor %q", arg)
+    }
+    return func(img image.Image) image.Image { return brightness(img, factor) }, nil
+  }
+  return nil, fmt.Errorf("unknown night transform %q", spec)
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+  "fmt"
+  "image"
+  "image/color"
+  "image/draw"
+  "path/filepath"
+  "strings"
+  "github.com/nfnt/resize"
+)
+
+// isNinePatch reports whether filename is a nine-patch, e.g. bubble.9.png.
+func isNinePatch(filename string) bool {
+  return strings.HasSuffix(strings.TrimSuffix(filename, ".png"), ".9")
+}
+
+// resizeNinePatch scales the content of a nine-patch to width x height,
+// without its 1px border, and redraws the border's stretch and padding
+// markers at the new size so they stay 1px wide and black.
+func resizeNinePatch(img image.Image, width int, height int) image.Image {
+  bounds := img.Bounds()
+  inner := image.Rect(bounds.Min.X + 1, bounds.Min.Y + 1, bounds.Max.X - 1, bounds.Max.Y - 1)
+  content := image.NewNRGBA(image.Rect(0, 0, inner.Dx(), inner.Dy()))
+  draw.Draw(content, content.Rect, img, inner.Min, draw.Src)
+
+  out := image.NewNRGBA(image.Rect(0, 0, width + 2, height + 2))
+  draw.Draw(out, image.Rect(1, 1, width + 1, height + 1), resize.Resize(uint(width), uint(height), content, resizeFilter()), image.Point{}, draw.Src)
+
+  black := color.NRGBA{A: 255}
+  for _, side := range []struct {
+    get func(i int) color.Color
+    set func(i int)
+    from, to int
+  }{
+    {func(i int) color.Color { return img.At(inner.Min.X + i, bounds.Min.Y) }, func(i int) { out.Set(1 + i, 0, black) }, inner.Dx(), width},
+    {func(i int) color.Color { return img.At(inner.Min.X + i, bounds.Max.Y - 1) }, func(i int) { out.Set(1 + i, height + 1, black) }, inner.Dx(), width},
+    {func(i int) color.Color { return img.At(bounds.Min.X, inner.Min.Y + i) }, func(i int) { out.Set(0, 1 + i, black) }, inner.Dy(), height},
+    {func(i int) color.Color { return img.At(bounds.Max.X - 1, inner.Min.Y + i) }, func(i int) { out.Set(width + 1, 1 + i, black) }, inner.Dy(), height},
+  } {
+    for _, segment := range markerSegments(side.get, side.from) {
+      start, end := segment[0] * side.to / side.from, (segment[1] * side.to + side.from / 2) / side.from
+      if end <= start {
+        // never let a marker disappear, aapt needs every stretch region
+        end = start + 1
+      }
+      for i := start; i < end && i < side.to; i++ {
+        side.set(i)
+      }
+    }
+  }
+  return out
+}
+
+// transformNinePatch applies fn to the content of a nine-patch, leaving its
+// border markers alone.
+func transformNinePatch(img image.Image, fn transform) image.Image {
+  bounds := img.Bounds()
+  inner := image.Rect(bounds.Min.X + 1, bounds.Min.Y + 1, bounds.Max.X - 1, bounds.Max.Y - 1)
+  content := image.NewNRGBA(image.Rect(0, 0, inner.Dx(), inner.Dy()))
+  draw.Draw(content, content.Rect, img, inner.Min, draw.Src)
+
+  out := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+  draw.Draw(out, out.Rect, img, bounds.Min, draw.Src)
+  transformed := fn(content)
+  draw.Draw(out, image.Rect(1, 1, inner.Dx() + 1, inner.Dy() + 1), transformed, transformed.Bounds().Min, draw.Src)
+  return out
+}
+
+// markerSegments finds the runs of black pixels along one side of a
+// nine-patch's border, as [start, end) pairs.
+func markerSegments(at func(i int) color.Color, length int) (segments [][2]int) {
+  start := -1
+  for i := 0; i <= length; i++ {
+    marked := false
+    if i < length {
+      r, g, b, a := at(i).RGBA()
+      marked = a > 0x7fff && r < 0x8000 && g < 0x8000 && b < 0x8000
+    }
+    if marked && start < 0 {
+      start = i
+    } else if !marked && start >= 0 {
+      segments = append(segments, [2]int{start, i})
+      start = -1
+    }
+  }
+  return
+}
+
+// checkNinePatches flags nine-patches aapt will reject or render oddly: a
+// border with pixels other than opaque black (or red layout bounds) and
+// transparent, corners that aren't transparent, no stretch region along the
+// top or left, or more than one padding region along the bottom or right.
+func checkNinePatches(resFolders []string) (findings []Finding, err error) {
+  files, err := scanResFiles(resFolders)
+  if err != nil {
+    return nil, err
+  }
+  for _, file := range files {
+    if !isNinePatch(filepath.Base(file.Path)) {
+      continue
+    }
+    img, _, err := decodeImageFile(file.Path)
+    if err != nil {
+      return nil, fmt.Errorf("%s: %v", file.Path, err)
+    }
+    for _, problem := range ninePatchProblems(img) {
+      findings = append(findings, Finding{Check: "ninepatch", Path: file.Path, Message: problem})
+    }
+  }
+  return
+}
+
+func ninePatchProblems(img image.Image) (problems []string) {
+  bounds := img.Bounds()
+  if bounds.Dx() < 3 || bounds.Dy() < 3 {
+    return []string{fmt.Sprintf("is %dx%d, too small for a border and content", bounds.Dx(), bounds.Dy())}
+  }
+  inner := image.Rect(bounds.Min.X + 1, bounds.Min.Y + 1, bounds.Max.X - 1, bounds.Max.Y - 1)
+  for _, corner := range []image.Point{bounds.Min, {bounds.Max.X - 1, bounds.Min.Y}, {bounds.Min.X, bounds.Max.Y - 1}, bounds.Max.Sub(image.Pt(1, 1))} {
+    if _, _, _, a := img.At(corner.X, corner.Y).RGBA(); a != 0 {
+      problems = append(problems, fmt.Sprintf("has a corner at %d,%d that isn't transparent", corner.X - bounds.Min.X, corner.Y - bounds.Min.Y))
+      break
+    }
+  }
+  for _, side := range []struct {
+    name string
+    at func(i int) color.Color
+    length int
+    stretch bool
+  }{
+    {"top", func(i int) color.Color { return img.At(inner.Min.X + i, bounds.Min.Y) }, inner.Dx(), true},
+    {"left", func(i int) color.Color { return img.At(bounds.Min.X, inner.Min.Y + i) }, inner.Dy(), true},
+    {"bottom", func(i int) color.Color { return img.At(inner.Min.X + i, bounds.Max.Y - 1) }, inner.Dx(), false},
+    {"right", func(i int) color.Color { return img.At(bounds.Max.X - 1, inner.Min.Y + i) }, inner.Dy(), false},
+  } {
+    for i := 0; i < side.length; i++ {
+      if !borderColor(side.at(i)) {
+        r, g, b, a := side.at(i).RGBA()
+        problems = append(problems, fmt.Sprintf("has a #%02X%02X%02X%02X pixel in its %s border at %d, only black and transparent mark regions", a >> 8, r >> 8, g >> 8, b >> 8, side.name, i + 1))
+        break
+      }
+    }
+    segments := markerSegments(side.at, side.length)
+    switch {
+    case side.stretch && len(segments) == 0:
+      problems = append(problems, fmt.Sprintf("has no stretch region marked along its %s border", side.name))
+    case !side.stretch && len(segments) > 1:
+      problems = append(problems, fmt.Sprintf("has %d padding regions along its %s border, it can only have one", len(segments), side.name))
+    }
+  }
+  return
+}
+
+// borderColor reports whether c can be on a nine-patch's border: opaque
+// black for regions, opaque red for layout bounds, or transparent.
+func borderColor(c color.Color) bool {
+  r, g, b, a := c.RGBA()
+  switch {
+  case a == 0:
+    return true
+  case a != 0xffff:
+    return false
+  }
+  return g == 0 && b == 0 && (r == 0 || r == 0xffff)
+}
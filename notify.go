@@ -0,0 +1,59 @@
+package main
+
+import (
+  "fmt"
+  "log"
+  "path/filepath"
+  "strings"
+  "github.com/spf13/cobra"
+)
+
+const notificationIconDp = 24
+
+var (
+  notifyAlphaFrom string
+  notifySourceSets []string
+)
+
+var notifyCmd = &cobra.Command{
+  Use: "notify [assets]",
+  Short: "Turn assets into white-on-transparent notification icons at 24dp for every density.",
+  Run: func(cmd *cobra.Command, args []string) {
+    if len(args) < 1 {
+      log.Fatal("need one or more filenames.")
+    }
+    if err := checkOutputOptions(); err != nil {
+      log.Fatal(err)
+    }
+    if !cmd.Flags().Changed("source-set") {
+      notifySourceSets = config.SourceSets
+    }
+
+    resFolder, err := defaultResFolder()
+    if err != nil { log.Fatal(err) }
+
+    for _, arg := range args {
+      img, _, err := decodeImageFile(arg)
+      if err != nil { log.Fatal(err) }
+      icon, err := silhouette(img, notifyAlphaFrom)
+      if err != nil { log.Fatal(err) }
+
+      filename := withFormatExtension(filepath.Base(arg), "png")
+      fmt.Printf("%s %s\n", green("from"), arg)
+      if !strings.HasPrefix(filename, "ic_stat_") {
+        fmt.Printf("  %s notification icons are usually named ic_stat_*\n", yellow("warn"))
+      }
+      for _, target := range targetResFolders(resFolder, notifySourceSets) {
+        if err := writeDensitySet(target, "drawable", filename, icon, notificationIconDp, notificationIconDp); err != nil {
+          log.Fatal(err)
+        }
+      }
+    }
+  },
+}
+
+func init() {
+  addOutputFlags(notifyCmd)
+  notifyCmd.Flags().StringVar(&notifyAlphaFrom, "alpha-from", "alpha", "where the silhouette comes from: alpha, luminance or darkness")
+  notifyCmd.Flags().StringSliceVarP(&notifySourceSets, "source-set", "s", nil, "source sets to write generated assets into (e.g. main,paid or all)")
+}
@@ -0,0 +1,422 @@
+package main
+
+import (
+  "bufio"
+  "bytes"
+  "errors"
+  "fmt"
+  "image"
+  "io"
+  "math"
+  "os"
+  "path/filepath"
+  "strings"
+  "sync"
+  "github.com/nfnt/resize"
+  "github.com/spf13/cobra"
+)
+
+// OutputOptions controls how generated assets are written. It's shared by
+// every command that writes into the res tree.
+type OutputOptions struct {
+  MissingOnly bool
+  Force bool
+  SkipExisting bool
+  DryRun bool
+  Backup bool
+  Out string
+  Rounding string
+  RoundingWarn float64
+  // Filter resizes images, lanczos3 if empty
+  Filter string
+}
+
+var outputOptions OutputOptions
+
+// outputCounts tallies what writeFile and skipExisting did per asset they
+// were generating from, for summaries and andy.lock. Assets can be processed
+// in parallel, so it's guarded by outputMutex like the output itself.
+var (
+  outputMutex sync.Mutex
+  outputCounts = map[string]*fileCounts{}
+)
+
+type fileCounts struct {
+  Written int
+  Skipped int
+  // Hashes maps each file written to the hash of its contents
+  Hashes map[string]string
+}
+
+// progress is where printf goes, stderr when stdout is a report for scripts.
+var progress io.Writer = os.Stdout
+
+// printf prints whole lines, one goroutine at a time.
+func printf(format string, args ...interface{}) {
+  outputMutex.Lock()
+  defer outputMutex.Unlock()
+  fmt.Fprintf(progress, format, args...)
+}
+
+// countOutput records a written (with its contents) or skipped file under
+// the asset it was generated from, "" for files that aren't from one.
+func countOutput(asset string, path string, data []byte, skipped bool) {
+  outputMutex.Lock()
+  defer outputMutex.Unlock()
+  counts, ok := outputCounts[asset]
+  if !ok {
+    counts = &fileCounts{Hashes: map[string]string{}}
+    outputCounts[asset] = counts
+  }
+  if skipped {
+    counts.Skipped++
+  } else {
+    counts.Written++
+    counts.Hashes[path] = dataHash(data)
+  }
+}
+
+func addOutputFlags(cmd *cobra.Command) {
+  cmd.Flags().BoolVar(&outputOptions.MissingOnly, "missing-only", false, "only generate densities that don't have the asset yet")
+  cmd.Flags().BoolVar(&outputOptions.Force, "force", false, "overwrite existing files that differ without asking")
+  cmd.Flags().BoolVar(&outputOptions.SkipExisting, "skip-existing", false, "leave existing files that differ alone instead of asking")
+  cmd.Flags().BoolVar(&outputOptions.DryRun, "dry-run", false, "print the files that would be created or overwritten without writing anything")
+  if cmd.Flags().Lookup("out") == nil {
+    cmd.Flags().StringVar(&outputOptions.Out, "out", "", "res folder to write generated files into instead of the source's")
+  }
+  cmd.Flags().BoolVar(&outputOptions.Backup, "backup", false, "save files before replacing them into .andy/backup/<timestamp>/")
+  cmd.Flags().StringVar(&outputOptions.Rounding, "round", "floor", "how to round scaled pixel dimensions: ceil, floor, nearest or even")
+  cmd.Flags().Float64Var(&outputOptions.RoundingWarn, "round-warn", 0.25, "warn when rounding moves a dimension by more than this many pixels")
+}
+
+func checkOutputOptions() error {
+  if outputOptions.Force && outputOptions.SkipExisting {
+    return errors.New("--force and --skip-existing can't be used together")
+  }
+  if _, ok := roundingFuncs[outputOptions.Rounding]; !ok {
+    return fmt.Errorf("unknown rounding policy %q", outputOptions.Rounding)
+  }
+  return nil
+}
+
+var roundingFuncs = map[string]func(float64) float64{
+  "ceil":    math.Ceil,
+  "floor":   math.Floor,
+  "nearest": math.Round,
+  "even":    math.RoundToEven,
+}
+
+var resizeFilters = map[string]resize.InterpolationFunction{
+  "nearest":  resize.NearestNeighbor,
+  "bilinear": resize.Bilinear,
+  "bicubic":  resize.Bicubic,
+  "mitchell": resize.MitchellNetravali,
+  "lanczos2": resize.Lanczos2,
+  "lanczos3": resize.Lanczos3,
+}
+
+func resizeFilter() resize.InterpolationFunction {
+  if filter, ok := resizeFilters[outputOptions.Filter]; ok {
+    return filter
+  }
+  return resize.Lanczos3
+}
+
+// scaleDimension scales a pixel size between densities using the configured
+// rounding policy, never going below one pixel.
+func scaleDimension(size int, from dpi, to dpi) (scaled int, exact float64) {
+  exact = float64(size) * float64(to) / float64(from)
+  scaled = int(roundingFuncs[outputOptions.Rounding](exact))
+  if scaled < 1 {
+    scaled = 1
+  }
+  return
+}
+
+// skipExisting reports whether path should be left alone because of
+// --missing-only, printing why.
+func skipExisting(path string) bool {
+  return skipExistingOutput("", path)
+}
+
+// skipExistingOutput is skipExisting for a file generated from asset.
+func skipExistingOutput(asset string, path string) bool {
+  if !outputOptions.MissingOnly {
+    return false
+  }
+  if existing := existingVariant(filepath.Dir(path), filepath.Base(path)); existing != "" {
+    printf("  %s %s\n", yellow("skip"), existing)
+    countOutput(asset, path, nil, true)
+    return true
+  }
+  return false
+}
+
+// writeImage encodes img to path. Every generated image goes through here.
+func writeImage(path string, img image.Image, format string) error {
+  return writeOutputImage("", path, img, format)
+}
+
+// writeOutputImage is writeImage for an image generated from asset.
+func writeOutputImage(asset string, path string, img image.Image, format string) error {
+  var buf bytes.Buffer
+  if err := encodeImage(&buf, img, format); err != nil {
+    return fmt.Errorf("%s: %v", path, err)
+  }
+  if outputOptions.DryRun {
+    reportDryRun(asset, path, buf.Len(), img.Bounds().Size())
+    return nil
+  }
+  return writeOutput(asset, path, buf.Bytes())
+}
+
+// writeFile writes a generated file, creating its folder if needed. An
+// existing file with different contents is only replaced with --force or
+// when the user agrees to it, and is backed up first with --backup. Every
+// change goes in the journal for andy undo.
+func writeFile(path string, data []byte) error {
+  return writeOutput("", path, data)
+}
+
+// writeOutput is writeFile for a file generated from asset.
+func writeOutput(asset string, path string, data []byte) error {
+  if outputOptions.DryRun {
+    reportDryRun(asset, path, len(data), image.Point{})
+    return nil
+  }
+  existing, err := os.ReadFile(path)
+  entry := JournalEntry{Path: path, Created: err != nil}
+  if err == nil && !bytes.Equal(existing, data) {
+    if overwrite, err := confirmOverwrite(asset, path); !overwrite {
+      return err
+    }
+    if outputOptions.Backup {
+      if entry.Backup, err = backupFile(path, existing); err != nil {
+        return fmt.Errorf("backing up %s: %v", path, err)
+      }
+    }
+  }
+  if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+    return err
+  }
+  if err := writeAtomic(path, data); err != nil {
+    return err
+  }
+  if entry.Created || !bytes.Equal(existing, data) {
+    if err := journalFile(entry); err != nil {
+      return err
+    }
+  }
+  printf("  %s %s\n", green("->"), path)
+  countOutput(asset, path, data, false)
+  return nil
+}
+
+// writeAtomic writes data to a temp file next to path and renames it into
+// place, so an interrupted run never leaves a truncated file behind. The temp
+// name starts with a dot, which aapt ignores if one is left over.
+func writeAtomic(path string, data []byte) error {
+  file, err := os.CreateTemp(filepath.Dir(path), "." + filepath.Base(path) + ".*.tmp")
+  if err != nil {
+    return err
+  }
+  defer os.Remove(file.Name())
+  if _, err := file.Write(data); err != nil {
+    file.Close()
+    return err
+  }
+  if err := file.Sync(); err != nil {
+    file.Close()
+    return err
+  }
+  if err := file.Close(); err != nil {
+    return err
+  }
+  if err := os.Chmod(file.Name(), 0644); err != nil {
+    return err
+  }
+  return os.Rename(file.Name(), path)
+}
+
+var promptReader = bufio.NewReader(os.Stdin)
+
+// confirmOverwrite decides whether an existing file that differs gets
+// replaced: always with --force, never with --skip-existing, and otherwise by
+// asking, or refusing when there's no terminal to ask on. Answering all sets
+// --force, so it's read and set under outputMutex while other workers write.
+func confirmOverwrite(asset string, path string) (bool, error) {
+  outputMutex.Lock()
+  force := outputOptions.Force
+  outputMutex.Unlock()
+  if force {
+    return true, nil
+  }
+  if !outputOptions.SkipExisting && stdinIsPiped() {
+    return false, fmt.Errorf("%s exists and differs, pass --force to overwrite it or --skip-existing", path)
+  }
+  if !outputOptions.SkipExisting {
+    outputMutex.Lock()
+    // another worker may have been answered with all in the meantime
+    answer := "all"
+    if !outputOptions.Force {
+      fmt.Printf("  %s %s exists and differs, overwrite? [y/N/a(ll)] ", yellow("?"), path)
+      answer, _ = promptReader.ReadString('\n')
+    }
+    answer = strings.ToLower(strings.TrimSpace(answer))
+    if answer == "a" || answer == "all" {
+      outputOptions.Force = true
+    }
+    outputMutex.Unlock()
+    switch answer {
+    case "y", "yes", "a", "all":
+      return true, nil
+    }
+  }
+  printf("  %s %s\n", yellow("skip"), path)
+  countOutput(asset, path, nil, true)
+  return false, nil
+}
+
+// confirm asks question on the terminal and reports whether the answer was
+// yes. Without a terminal to ask on, the answer is no.
+func confirm(question string) bool {
+  if stdinIsPiped() {
+    return false
+  }
+  outputMutex.Lock()
+  defer outputMutex.Unlock()
+  fmt.Printf("%s %s [y/N] ", yellow("?"), question)
+  answer, _ := promptReader.ReadString('\n')
+  answer = strings.ToLower(strings.TrimSpace(answer))
+  return answer == "y" || answer == "yes"
+}
+
+// removeFile deletes a file from the res tree. It's always backed up first,
+// so andy undo can bring it back.
+func removeFile(path string) error {
+  data, err := os.ReadFile(path)
+  if err != nil {
+    return err
+  }
+  backup, err := backupFile(path, data)
+  if err != nil {
+    return fmt.Errorf("backing up %s: %v", path, err)
+  }
+  if err := os.Remove(path); err != nil {
+    return err
+  }
+  if err := journalFile(JournalEntry{Path: path, Backup: backup}); err != nil {
+    return err
+  }
+  printf("  %s %s\n", yellow("removed"), path)
+  return nil
+}
+
+// replaceFile writes data over a file that isn't an output, like a layout
+// whose references changed. Its old contents are always backed up.
+func replaceFile(path string, data []byte) error {
+  if outputOptions.DryRun {
+    reportDryRun("", path, len(data), image.Point{})
+    return nil
+  }
+  existing, err := os.ReadFile(path)
+  if err != nil {
+    return err
+  }
+  backup, err := backupFile(path, existing)
+  if err != nil {
+    return fmt.Errorf("backing up %s: %v", path, err)
+  }
+  if err := writeAtomic(path, data); err != nil {
+    return err
+  }
+  if err := journalFile(JournalEntry{Path: path, Backup: backup}); err != nil {
+    return err
+  }
+  printf("  %s %s\n", green("->"), path)
+  return nil
+}
+
+// moveFile renames a file in the res tree, journaled as a new file and a
+// removed one so andy undo can move it back.
+func moveFile(from string, to string) error {
+  data, err := os.ReadFile(from)
+  if err != nil {
+    return err
+  }
+  if info, err := os.Stat(to); err == nil {
+    // on case insensitive file systems, a rename that only changes case
+    // finds the file itself
+    if fromInfo, err := os.Stat(from); err != nil || !os.SameFile(info, fromInfo) {
+      return fmt.Errorf("%s already exists", to)
+    }
+  }
+  backup, err := backupFile(from, data)
+  if err != nil {
+    return fmt.Errorf("backing up %s: %v", from, err)
+  }
+  if err := os.Rename(from, to); err != nil {
+    return err
+  }
+  // undo goes backwards, removing the new file before restoring the old one
+  if err := journalFile(JournalEntry{Path: from, Backup: backup}); err != nil {
+    return err
+  }
+  if err := journalFile(JournalEntry{Path: to, Created: true}); err != nil {
+    return err
+  }
+  printf("  %s %s %s\n", from, green("->"), to)
+  return nil
+}
+
+// reportDryRun prints what writing size bytes to path would do, with the
+// before and after dimensions for images.
+func reportDryRun(asset string, path string, size int, dimens image.Point) {
+  after := formatBytes(int64(size))
+  if dimens != (image.Point{}) {
+    after += fmt.Sprintf(" %dx%d", dimens.X, dimens.Y)
+  }
+  countOutput(asset, path, nil, false)
+  info, err := os.Stat(path)
+  if err != nil {
+    printf("  %s %s (%s)\n", green("create"), path, after)
+    return
+  }
+  before := formatBytes(info.Size())
+  if dimens != (image.Point{}) {
+    if file, err := os.Open(path); err == nil {
+      if existing, _, err := image.DecodeConfig(file); err == nil {
+        before += fmt.Sprintf(" %dx%d", existing.Width, existing.Height)
+      }
+      file.Close()
+    }
+  }
+  printf("  %s %s (%s -> %s)\n", yellow("overwrite"), path, before, after)
+}
+
+func formatBytes(size int64) string {
+  switch {
+  case size >= 1 << 20:
+    return fmt.Sprintf("%.1fMB", float64(size) / (1 << 20))
+  case size >= 1 << 10:
+    return fmt.Sprintf("%.1fKB", float64(size) / (1 << 10))
+  }
+  return fmt.Sprintf("%dB", size)
+}
+
+// existingVariant returns the path of a file in dir with the same resource
+// name as filename, whatever its extension, or "" if there is none.
+func existingVariant(dir string, filename string) string {
+  name := strings.TrimSuffix(filename, filepath.Ext(filename))
+  entries, err := os.ReadDir(dir)
+  if err != nil {
+    return ""
+  }
+  for _, entry := range entries {
+    entryName := entry.Name()
+    if !entry.IsDir() && strings.TrimSuffix(entryName, filepath.Ext(entryName)) == name {
+      return filepath.Join(dir, entryName)
+    }
+  }
+  return ""
+}
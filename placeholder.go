@@ -0,0 +1,111 @@
+package main
+
+import (
+  "fmt"
+  "image"
+  "image/color"
+  "image/draw"
+  "log"
+  "math"
+  "github.com/nfnt/resize"
+  "github.com/spf13/cobra"
+  "golang.org/x/image/font"
+  "golang.org/x/image/font/basicfont"
+  "golang.org/x/image/math/fixed"
+)
+
+var (
+  placeholderSize string
+  placeholderColor string
+  placeholderLabel string
+  placeholderSourceSets []string
+)
+
+var placeholderCmd = &cobra.Command{
+  Use: "placeholder [names]",
+  Short: "Create labeled solid-color placeholder drawables in every density.",
+  Run: func(cmd *cobra.Command, args []string) {
+    if len(args) < 1 {
+      log.Fatal("need one or more drawable names.")
+    }
+    if err := checkOutputOptions(); err != nil {
+      log.Fatal(err)
+    }
+    if !cmd.Flags().Changed("source-set") {
+      placeholderSourceSets = config.SourceSets
+    }
+    widthDp, heightDp, err := parseDpSize(placeholderSize)
+    if err != nil { log.Fatal(err) }
+    fill, err := parseHexColor(placeholderColor)
+    if err != nil { log.Fatal(err) }
+    label := placeholderLabel
+    if !cmd.Flags().Changed("label") {
+      label = fmt.Sprintf("%gx%g", widthDp, heightDp)
+    }
+
+    img := renderPlaceholder(widthDp, heightDp, fill, label)
+    resFolder, err := defaultResFolder()
+    if err != nil { log.Fatal(err) }
+    for _, name := range args {
+      fmt.Printf("%s %s\n", green("placeholder"), name)
+      for _, target := range targetResFolders(resFolder, placeholderSourceSets) {
+        if err := writeDensitySet(target, "drawable", withFormatExtension(name, "png"), img, widthDp, heightDp); err != nil {
+          log.Fatal(err)
+        }
+      }
+    }
+  },
+}
+
+func init() {
+  addOutputFlags(placeholderCmd)
+  placeholderCmd.Flags().StringVar(&placeholderSize, "dp", "48x48", "size of the placeholder in dp")
+  placeholderCmd.Flags().StringVar(&placeholderColor, "color", "#FF4081", "fill color")
+  placeholderCmd.Flags().StringVar(&placeholderLabel, "label", "", "text to draw on the placeholder (default: its size)")
+  placeholderCmd.Flags().StringSliceVarP(&placeholderSourceSets, "source-set", "s", nil, "source sets to write generated assets into (e.g. main,paid or all)")
+}
+
+// renderPlaceholder draws the placeholder at xxxhdpi-or-better resolution
+// with the label centered in black or white, whichever reads better.
+func renderPlaceholder(widthDp float64, heightDp float64, fill color.NRGBA, label string) image.Image {
+  const pxPerDp = 4
+  width, height := int(widthDp*pxPerDp), int(heightDp*pxPerDp)
+  img := solidImage(width, height, fill)
+  if label == "" {
+    return img
+  }
+
+  ink := color.Color(color.White)
+  if 0.2126*float64(fill.R) + 0.7152*float64(fill.G) + 0.0722*float64(fill.B) > 140 {
+    ink = color.Black
+  }
+  scaled := renderText(label, ink, int(0.8*float64(width)), int(0.4*float64(height)))
+  if scaled == nil {
+    return img
+  }
+  scaledWidth, scaledHeight := getDimens(&scaled)
+  offset := image.Pt((width-scaledWidth)/2, (height-scaledHeight)/2)
+  draw.Draw(img, scaled.Bounds().Add(offset), scaled, image.Point{}, draw.Over)
+  return img
+}
+
+// renderText draws label in ink, scaled up to fit maxWidth x maxHeight. It
+// returns nil if the text would be too small to draw.
+func renderText(label string, ink color.Color, maxWidth int, maxHeight int) image.Image {
+  face := basicfont.Face7x13
+  textWidth := font.MeasureString(face, label).Ceil()
+  textHeight := face.Metrics().Height.Ceil()
+  if textWidth == 0 {
+    return nil
+  }
+  text := image.NewNRGBA(image.Rect(0, 0, textWidth, textHeight))
+  drawer := font.Drawer{Dst: text, Src: image.NewUniform(ink), Face: face, Dot: fixed.P(0, face.Metrics().Ascent.Ceil())}
+  drawer.DrawString(label)
+
+  scale := math.Min(float64(maxWidth)/float64(textWidth), float64(maxHeight)/float64(textHeight))
+  scaledWidth, scaledHeight := int(float64(textWidth)*scale), int(float64(textHeight)*scale)
+  if scaledWidth < 1 || scaledHeight < 1 {
+    return nil
+  }
+  return resize.Resize(uint(scaledWidth), uint(scaledHeight), text, resize.Bilinear)
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+  "encoding/json"
+  "fmt"
+  "io"
+  "log"
+  "net/http"
+  "net/url"
+  "os"
+  "sort"
+  "strings"
+  "time"
+  "github.com/spf13/cobra"
+)
+
+var (
+  figmaFileKey string
+  figmaNodes []string
+  figmaToken string
+  figmaScale float64
+)
+
+var pullCmd = &cobra.Command{
+  Use: "pull",
+  Short: "Pull assets from a design tool's API into the res folder.",
+}
+
+var pullFigmaCmd = &cobra.Command{
+  Use: "figma --file-key <key> --node <id>...",
+  Short: "Export frames and components from Figma and generate their densities.",
+  Long: `Export frames and components from Figma and generate their densities.
+
+The nodes are rendered by the Figma REST API at --scale (4x by default, the
+most Figma allows, which is xxxhdpi), saved into the matching density folder
+and resized into every lower density like andy dpi does. Each is named after
+its layer, made into a valid resource name: "Icons / Send" becomes
+icons_send.
+
+The API token comes from --token or FIGMA_TOKEN. Node ids are in the url of
+a selected layer, like node-id=12-34, and can be given as 12:34 or 12-34.
+
+  andy pull figma --file-key FpK3xJq2 --node 12:34 --node 12:35`,
+  Run: func(cmd *cobra.Command, args []string) {
+    if figmaFileKey == "" || len(figmaNodes) == 0 {
+      log.Fatal("need a --file-key and one or more --node ids.")
+    }
+    if figmaToken == "" {
+      figmaToken = os.Getenv("FIGMA_TOKEN")
+    }
+    if figmaToken == "" {
+      log.Fatal("need a Figma API token, pass --token or set FIGMA_TOKEN.")
+    }
+    if err := checkOutputOptions(); err != nil { log.Fatal(err) }
+    density, err := scaleDensity(figmaScale)
+    if err != nil { log.Fatal(err) }
+    var ids []string
+    for _, node := range figmaNodes {
+      ids = append(ids, strings.ReplaceAll(node, "-", ":"))
+    }
+
+    client := figmaClient{Token: figmaToken, HTTP: &http.Client{Timeout: time.Minute}}
+    names, err := client.nodeNames(figmaFileKey, ids)
+    if err != nil { log.Fatal(err) }
+    urls, err := client.imageURLs(figmaFileKey, ids, figmaScale)
+    if err != nil { log.Fatal(err) }
+    var images []importedImage
+    for _, id := range ids {
+      if urls[id] == "" {
+        log.Fatalf("figma couldn't render node %s", id)
+      }
+      printf("%s %s (%s)\n", green("pull"), names[id], id)
+      data, err := client.download(urls[id])
+      if err != nil { log.Fatal(err) }
+      images = append(images, importedImage{Name: names[id], Density: density, Data: data, Ext: ".png"})
+    }
+    if err := importImages(images); err != nil { log.Fatal(err) }
+  },
+}
+
+func init() {
+  pullFigmaCmd.Flags().StringVar(&figmaFileKey, "file-key", "", "key of the Figma file, from its url")
+  pullFigmaCmd.Flags().StringSliceVar(&figmaNodes, "node", nil, "ids of the frames or components to export")
+  pullFigmaCmd.Flags().StringVar(&figmaToken, "token", "", "Figma personal access token (default $FIGMA_TOKEN)")
+  pullFigmaCmd.Flags().Float64Var(&figmaScale, "scale", 4, "scale to export at, 4 for xxxhdpi")
+  addOutputFlags(pullFigmaCmd)
+  addMasterFlags(pullFigmaCmd)
+  pullCmd.AddCommand(pullFigmaCmd)
+}
+
+const figmaAPI = "https://api.figma.com/v1"
+
+type figmaClient struct {
+  Token string
+  HTTP *http.Client
+}
+
+func (client figmaClient) get(rawURL string, auth bool) ([]byte, error) {
+  req, err := http.NewRequest("GET", rawURL, nil)
+  if err != nil {
+    return nil, err
+  }
+  if auth {
+    req.Header.Set("X-Figma-Token", client.Token)
+  }
+  resp, err := client.HTTP.Do(req)
+  if err != nil {
+    return nil, err
+  }
+  defer resp.Body.Close()
+  body, err := io.ReadAll(resp.Body)
+  if err != nil {
+    return nil, err
+  }
+  if resp.StatusCode != http.StatusOK {
+    var apiErr struct {
+      Err string `json:"err"`
+      Message string `json:"message"`
+    }
+    json.Unmarshal(body, &apiErr)
+    if apiErr.Err == "" {
+      apiErr.Err = apiErr.Message
+    }
+    return nil, fmt.Errorf("figma: %s %s", resp.Status, apiErr.Err)
+  }
+  return body, nil
+}
+
+// nodeNames maps each node id to its layer name.
+func (client figmaClient) nodeNames(fileKey string, ids []string) (map[string]string, error) {
+  body, err := client.get(fmt.Sprintf("%s/files/%s/nodes?ids=%s", figmaAPI, url.PathEscape(fileKey), url.QueryEscape(strings.Join(ids, ","))), true)
+  if err != nil {
+    return nil, err
+  }
+  var response struct {
+    Nodes map[string]*struct {
+      Document struct {
+        Name string `json:"name"`
+      } `json:"document"`
+    } `json:"nodes"`
+  }
+  if err := json.Unmarshal(body, &response); err != nil {
+    return nil, fmt.Errorf("figma: %v", err)
+  }
+  names := map[string]string{}
+  var missing []string
+  for _, id := range ids {
+    if node := response.Nodes[id]; node != nil {
+      names[id] = node.Document.Name
+    } else {
+      missing = append(missing, id)
+    }
+  }
+  if len(missing) > 0 {
+    sort.Strings(missing)
+    return nil, fmt.Errorf("figma: no nodes %s in file %s", strings.Join(missing, ", "), fileKey)
+  }
+  return names, nil
+}
+
+// imageURLs asks Figma to render the nodes as png at scale, and maps each id
+// to where its render can be downloaded.
+func (client figmaClient) imageURLs(fileKey string, ids []string, scale float64) (map[string]string, error) {
+  body, err := client.get(fmt.Sprintf("%s/images/%s?ids=%s&scale=%g&format=png", figmaAPI, url.PathEscape(fileKey), url.QueryEscape(strings.Join(ids, ",")), scale), true)
+  if err != nil {
+    return nil, err
+  }
+  var response struct {
+    Err *string `json:"err"`
+    Images map[string]string `json:"images"`
+  }
+  if err := json.Unmarshal(body, &response); err != nil {
+    return nil, fmt.Errorf("figma: %v", err)
+  }
+  if response.Err != nil && *response.Err != "" {
+    return nil, fmt.Errorf("figma: %s", *response.Err)
+  }
+  return response.Images, nil
+}
+
+// download fetches a render. They're on a CDN, so without the token.
+func (client figmaClient) download(rawURL string) ([]byte, error) {
+  return client.get(rawURL, false)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+  "fmt"
+  "log"
+  "path/filepath"
+  "runtime"
+  "sort"
+  "github.com/spf13/cobra"
+)
+
+var (
+  regenJobs int
+  regenChanged bool
+)
+
+var regenCmd = &cobra.Command{
+  Use: "regen",
+  Short: "Rebuild every asset recorded in andy.lock from its source.",
+  Long: `Rebuild every asset recorded in andy.lock from its source.
+
+Each source is resized again with the options andy dpi used for it, so the
+generated densities always come from the masters. Generated files that were
+changed by hand are overwritten unless --skip-existing is passed.
+
+--changed only rebuilds the sources that changed since andy.lock or are
+missing a generated file, which makes it cheap enough to run before every
+build. Generated files edited by hand are left for andy check --generated to
+report.
+
+  andy regen
+  andy regen --changed`,
+  Run: func(cmd *cobra.Command, args []string) {
+    if err := checkOutputOptions(); err != nil {
+      log.Fatal(err)
+    }
+    if regenJobs < 1 {
+      log.Fatal("--jobs must be at least 1.")
+    }
+    if err := loadLock(); err != nil { log.Fatal(err) }
+    if len(lockedAssets) == 0 && !regenChanged {
+      log.Fatalf("nothing to regenerate, %s has no assets", lockFile)
+    }
+    outputOptions.Force = !outputOptions.SkipExisting
+
+    var sources []string
+    for source := range lockedAssets {
+      if !regenChanged || staleSource(lockedAssets[source]) {
+        sources = append(sources, source)
+      }
+    }
+    if len(sources) == 0 {
+      fmt.Printf("%s nothing changed since %s\n", green("unchanged"), lockFile)
+      return
+    }
+    assets, failures := regenerateLocked(sources)
+    printSummary(assets, failures)
+  },
+}
+
+func init() {
+  addOutputFlags(regenCmd)
+  regenCmd.Flags().IntVarP(&regenJobs, "jobs", "j", runtime.NumCPU(), "number of assets to process at once")
+  regenCmd.Flags().BoolVar(&regenChanged, "changed", false, "only rebuild sources that changed since andy.lock or are missing a generated file")
+}
+
+// staleSource reports whether asset's source changed since it was locked or
+// one of its outputs is gone.
+func staleSource(asset LockedAsset) bool {
+  if hash, err := fileHash(filepath.FromSlash(asset.Source)); err != nil || hash != asset.Hash {
+    return true
+  }
+  for output := range asset.Outputs {
+    if !fileExists(filepath.FromSlash(output)) {
+      return true
+    }
+  }
+  return false
+}
+
+// regenerateLocked rebuilds the locked sources, given as in andy.lock, with their
+// recorded options. Sources generated with the same options are processed
+// together.
+func regenerateLocked(sources []string) (assets []string, failures []error) {
+  groups := map[string][]string{}
+  groupOptions := map[string]DpiOptions{}
+  for _, source := range sources {
+    asset := lockedAssets[source]
+    key := fmt.Sprintf("%+v", asset.Options)
+    groups[key] = append(groups[key], filepath.FromSlash(source))
+    groupOptions[key] = asset.Options
+  }
+  var keys []string
+  for key := range groups {
+    keys = append(keys, key)
+    sort.Strings(groups[key])
+  }
+  sort.Strings(keys)
+
+  for _, key := range keys {
+    assets = append(assets, groups[key]...)
+    failures = append(failures, dpitize(groups[key], groupOptions[key], regenJobs, true)...)
+  }
+  return
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+  "encoding/json"
+  "fmt"
+  "net/url"
+  "os"
+  "path/filepath"
+  "strings"
+  "github.com/spf13/cobra"
+)
+
+// reportFormat is the --output of the commands that report on the res tree.
+var (
+  reportFormat string
+  reportFormats = map[string][]string{}
+)
+
+// addReportFlags adds --output to cmd, taking one of formats, the first
+// being the default.
+func addReportFlags(cmd *cobra.Command, formats ...string) {
+  reportFormats[cmd.Name()] = formats
+  cmd.Flags().StringVar(&reportFormat, "output", formats[0], "output format: " + strings.Join(formats, ", "))
+}
+
+// checkReportFormat validates --output for cmd. Reports for scripts own
+// stdout, so progress moves to stderr.
+func checkReportFormat(cmd *cobra.Command) error {
+  formats := reportFormats[cmd.Name()]
+  if !containsString(formats, reportFormat) {
+    return fmt.Errorf("unknown output format %q, expected %s", reportFormat, strings.Join(formats, " or "))
+  }
+  if reportFormat != "table" {
+    progress = os.Stderr
+  }
+  return nil
+}
+
+func printJSON(value interface{}) error {
+  encoder := json.NewEncoder(os.Stdout)
+  encoder.SetIndent("", "  ")
+  return encoder.Encode(value)
+}
+
+// fileJSON is a file of the res tree in JSON reports.
+type fileJSON struct {
+  Path string `json:"path"`
+  Density string `json:"density"`
+  Width int `json:"width"`
+  Height int `json:"height"`
+  Bytes int64 `json:"bytes"`
+}
+
+func (file resFile) JSON() fileJSON {
+  dimens, _ := file.Dimens()
+  return fileJSON{Path: filepath.ToSlash(file.Path), Density: densityToCanonical[file.Density], Width: dimens.X, Height: dimens.Y, Bytes: file.Size}
+}
+
+// findingsJSON is the report of a command that checks for problems.
+type findingsJSON struct {
+  Checked int `json:"checked"`
+  Findings []Finding `json:"findings"`
+}
+
+// printFindingsReport prints findings as json, sarif or github.
+func printFindingsReport(checked int, findings []Finding) error {
+  switch reportFormat {
+  case "sarif":
+    return printJSON(sarifReport(findings))
+  case "github":
+    for _, finding := range findings {
+      file := ""
+      if finding.Path != "" {
+        file = "file=" + githubEscape(filepath.ToSlash(finding.Path), true) + ","
+      }
+      fmt.Printf("::%s %stitle=andy %s::%s\n", findingLevel(finding), file, finding.Check, githubEscape(findingText(filepath.Base(finding.Path), finding), false))
+    }
+    return nil
+  }
+  return printJSON(findingsJSON{Checked: checked, Findings: append([]Finding{}, findings...)})
+}
+
+// findingText is the message of finding after path, or alone for findings
+// that aren't about a file, like the total budget, which have no path.
+func findingText(path string, finding Finding) string {
+  if finding.Path == "" {
+    return finding.Message
+  }
+  return path + " " + finding.Message
+}
+
+// findingLevel is warning, or error for the checks that fail builds on
+// purpose.
+func findingLevel(finding Finding) string {
+  if finding.Check == "budget" {
+    return "error"
+  }
+  return "warning"
+}
+
+// githubEscape escapes a workflow command's message, or one of its
+// properties, which need , and : escaped too.
+func githubEscape(value string, property bool) string {
+  value = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(value)
+  if property {
+    value = strings.NewReplacer(":", "%3A", ",", "%2C").Replace(value)
+  }
+  return value
+}
+
+// SARIF 2.1.0, just what code scanning needs to show findings inline.
+type sarifLog struct {
+  Version string `json:"version"`
+  Schema string `json:"$schema"`
+  Runs []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+  Tool struct {
+    Driver struct {
+      Name string `json:"name"`
+      InformationURI string `json:"informationUri"`
+      Rules []sarifRule `json:"rules"`
+    } `json:"driver"`
+  } `json:"tool"`
+  Results []sarifResult `json:"results"`
+}
+
+type sarifRule struct {
+  ID string `json:"id"`
+}
+
+type sarifResult struct {
+  RuleID string `json:"ruleId"`
+  Level string `json:"level"`
+  Message struct {
+    Text string `json:"text"`
+  } `json:"message"`
+  Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+  PhysicalLocation struct {
+    ArtifactLocation struct {
+      URI string `json:"uri"`
+    } `json:"artifactLocation"`
+  } `json:"physicalLocation"`
+}
+
+func sarifReport(findings []Finding) sarifLog {
+  run := sarifRun{Results: []sarifResult{}}
+  run.Tool.Driver.Name = "andy"
+  run.Tool.Driver.InformationURI = "https://github.com/mcginty/andy"
+  run.Tool.Driver.Rules = []sarifRule{}
+  for _, finding := range findings {
+    if !containsRule(run.Tool.Driver.Rules, finding.Check) {
+      run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: finding.Check})
+    }
+    result := sarifResult{RuleID: finding.Check, Level: findingLevel(finding)}
+    result.Message.Text = findingText(filepath.Base(finding.Path), finding)
+    // without a path it's a result of the whole run
+    if finding.Path != "" {
+      var location sarifLocation
+      location.PhysicalLocation.ArtifactLocation.URI = (&url.URL{Path: filepath.ToSlash(finding.Path)}).String()
+      result.Locations = []sarifLocation{location}
+    }
+    run.Results = append(run.Results, result)
+  }
+  return sarifLog{Version: "2.1.0", Schema: "https://json.schemastore.org/sarif-2.1.0.json", Runs: []sarifRun{run}}
+}
+
+func containsRule(rules []sarifRule, id string) bool {
+  for _, rule := range rules {
+    if rule.ID == id {
+      return true
+    }
+  }
+  return false
+}
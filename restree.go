@@ -0,0 +1,144 @@
+package main
+
+import (
+  "image"
+  "os"
+  "path/filepath"
+  "sort"
+  "strings"
+)
+
+// resFile is a bitmap in a density folder of a res tree, e.g.
+// res/drawable-night-xxhdpi/ic_logo.png.
+type resFile struct {
+  Path string
+  ResFolder string
+  // Type is drawable or mipmap
+  Type string
+  // Qualifiers are the folder's other qualifiers, e.g. night or ldrtl-v21
+  Qualifiers string
+  Density dpi
+  Name string
+  Size int64
+}
+
+// Resource names the drawable a file is a density of, e.g. ic_logo or
+// mipmap/ic_launcher (night).
+func (file resFile) Resource() string {
+  name := file.Name
+  if file.Type != "drawable" {
+    name = file.Type + "/" + name
+  }
+  if file.Qualifiers != "" {
+    name += " (" + file.Qualifiers + ")"
+  }
+  return name
+}
+
+// Dimens decodes just the header of the file for its size in pixels.
+func (file resFile) Dimens() (image.Point, error) {
+  f, err := os.Open(file.Path)
+  if err != nil {
+    return image.Point{}, err
+  }
+  defer f.Close()
+  config, _, err := image.DecodeConfig(f)
+  return image.Pt(config.Width, config.Height), err
+}
+
+// parseResFolder splits a folder name like drawable-night-xxhdpi into its
+// type, other qualifiers and density. ok is false for folders without one of
+// the configured densities.
+func parseResFolder(folder string) (resType string, qualifiers string, density dpi, ok bool) {
+  if density, ok = folderToDensity[folder]; ok {
+    return strings.SplitN(folder, "-", 2)[0], "", density, true
+  }
+  parts := strings.Split(folder, "-")
+  if parts[0] != "drawable" && parts[0] != "mipmap" {
+    return "", "", 0, false
+  }
+  var rest []string
+  for _, part := range parts[1:] {
+    found := false
+    for bucket, canonical := range densityToCanonical {
+      if part == canonical {
+        density, found = bucket, true
+      }
+    }
+    if !found {
+      rest = append(rest, part)
+    }
+  }
+  return parts[0], strings.Join(rest, "-"), density, density != 0
+}
+
+// scanResFiles lists the bitmaps in every density folder of resFolders,
+// leaving out what .andyignore excludes.
+func scanResFiles(resFolders []string) (files []resFile, err error) {
+  for _, resFolder := range resFolders {
+    entries, err := os.ReadDir(resFolder)
+    if err != nil {
+      return nil, err
+    }
+    for _, entry := range entries {
+      resType, qualifiers, density, ok := parseResFolder(entry.Name())
+      if !entry.IsDir() || !ok {
+        continue
+      }
+      dir := filepath.Join(resFolder, entry.Name())
+      images, err := os.ReadDir(dir)
+      if err != nil {
+        return nil, err
+      }
+      for _, file := range images {
+        name := file.Name()
+        ext := filepath.Ext(name)
+        path := filepath.Join(dir, name)
+        if file.IsDir() || !isBitmapExt(ext) || ignored(path, false) {
+          continue
+        }
+        info, err := file.Info()
+        if err != nil {
+          return nil, err
+        }
+        files = append(files, resFile{Path: path, ResFolder: resFolder, Type: resType, Qualifiers: qualifiers, Density: density, Name: strings.TrimSuffix(strings.TrimSuffix(name, ext), ".9"), Size: info.Size()})
+      }
+    }
+  }
+  return
+}
+
+// groupResources groups files by the resource they're densities of, and
+// lists the resources in order.
+func groupResources(files []resFile) (map[string][]resFile, []string) {
+  groups := map[string][]resFile{}
+  for _, file := range files {
+    groups[file.Resource()] = append(groups[file.Resource()], file)
+  }
+  var names []string
+  for name, group := range groups {
+    names = append(names, name)
+    sort.Slice(group, func(i, j int) bool { return group[i].Density < group[j].Density })
+  }
+  sort.Strings(names)
+  return groups, names
+}
+
+// filesNamed picks the files of the drawable called name, in every type,
+// density and qualifier folder. name can be qualified with its type, like
+// mipmap/ic_launcher, to pick only that type.
+func filesNamed(files []resFile, name string) (named []resFile) {
+  for _, file := range files {
+    if file.Name == name || file.resourceKey() == name {
+      named = append(named, file)
+    }
+  }
+  return
+}
+
+// Renamed is the path file would have as drawable name, keeping its
+// extension and nine-patch suffix.
+func (file resFile) Renamed(name string) string {
+  base := filepath.Base(file.Path)
+  return filepath.Join(filepath.Dir(file.Path), name + strings.TrimPrefix(base, file.Name))
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+  "fmt"
+  "image"
+  "image/color"
+  "image/draw"
+  "log"
+  "math"
+  "path/filepath"
+  "github.com/spf13/cobra"
+)
+
+var (
+  ribbonName string
+  ribbonColor string
+  ribbonCorner string
+  ribbonSourceSets []string
+)
+
+var ribbonCmd = &cobra.Command{
+  Use: "ribbon [label]",
+  Short: "Overlay a corner ribbon (DEBUG, BETA, a version) on the launcher icons into another source set.",
+  Long: `Overlay a corner ribbon (DEBUG, BETA, a version) on the launcher icons into another source set.
+
+The icons are read from the main res folder and the ribboned copies are
+written into the --source-set folders (debug by default), so each build type
+gets its own icon. Adaptive icons get the ribbon on their foreground layer,
+inside the part the launcher mask shows.`,
+  Run: func(cmd *cobra.Command, args []string) {
+    if len(args) != 1 {
+      log.Fatal("need the ribbon label, e.g. DEBUG.")
+    }
+    if err := checkOutputOptions(); err != nil {
+      log.Fatal(err)
+    }
+    if _, ok := ribbonCorners[ribbonCorner]; !ok {
+      log.Fatalf("unknown corner %q", ribbonCorner)
+    }
+    fill, err := parseHexColor(ribbonColor)
+    if err != nil { log.Fatal(err) }
+
+    resFolder, err := defaultResFolder()
+    if err != nil { log.Fatal(err) }
+    targets := targetResFolders(resFolder, ribbonSourceSets)
+    for _, target := range targets {
+      if target == resFolder {
+        log.Fatal("refusing to overwrite the source icons, pick another --source-set.")
+      }
+    }
+
+    layers := map[string]float64{
+      ribbonName: 0,
+      ribbonName + "_round": 0,
+      ribbonName + "_foreground": float64(adaptiveIconDp-adaptiveVisibleDp) / 2 / adaptiveIconDp,
+    }
+    found := false
+    for _, density := range ascendingDensityList {
+      folder := resourceFolder("mipmap", density)
+      for _, name := range []string{ribbonName, ribbonName + "_round", ribbonName + "_foreground"} {
+        source := filepath.Join(resFolder, folder, name + ".png")
+        if !fileExists(source) {
+          continue
+        }
+        found = true
+        icon, _, err := decodeImageFile(source)
+        if err != nil { log.Fatal(err) }
+        fmt.Printf("%s %s\n", green("from"), source)
+        ribboned := drawRibbon(icon, args[0], fill, ribbonCorner, layers[name])
+        for _, target := range targets {
+          path := filepath.Join(target, folder, name + ".png")
+          if skipExisting(path) {
+            continue
+          }
+          if err := writeImage(path, ribboned, "png"); err != nil {
+            log.Fatal(err)
+          }
+        }
+      }
+    }
+    if !found {
+      log.Fatalf("no %s icons found in %s", ribbonName, resFolder)
+    }
+  },
+}
+
+func init() {
+  addOutputFlags(ribbonCmd)
+  ribbonCmd.Flags().StringVar(&ribbonName, "name", "ic_launcher", "resource name of the launcher icon")
+  ribbonCmd.Flags().StringVar(&ribbonColor, "color", "#E53935", "ribbon color")
+  ribbonCmd.Flags().StringVar(&ribbonCorner, "corner", "top-left", "corner for the ribbon: top-left, top-right, bottom-left or bottom-right")
+  ribbonCmd.Flags().StringSliceVarP(&ribbonSourceSets, "source-set", "s", []string{"debug"}, "source sets to write the ribboned icons into")
+}
+
+// ribbonCorners says which axes to mirror to move a top-left ribbon into each
+// corner.
+var ribbonCorners = map[string][2]bool{
+  "top-left": {false, false},
+  "top-right": {true, false},
+  "bottom-left": {false, true},
+  "bottom-right": {true, true},
+}
+
+// drawRibbon lays a diagonal band with label across a corner of icon. inset
+// is the fraction of the icon on each side that is outside the visible area,
+// for adaptive icon layers.
+func drawRibbon(icon image.Image, label string, fill color.NRGBA, corner string, inset float64) image.Image {
+  out := image.NewNRGBA(image.Rect(0, 0, icon.Bounds().Dx(), icon.Bounds().Dy()))
+  draw.Draw(out, out.Rect, icon, icon.Bounds().Min, draw.Src)
+  width, _ := getDimens(&icon)
+  origin := float64(width) * inset
+  size := float64(width) - 2*origin
+
+  // the band's center line cuts the corner at half the icon's size
+  thickness := size * 0.16
+  distance := size * 0.5 / math.Sqrt2
+  length := 2 * (distance + thickness/2)
+  strip := solidImage(int(math.Ceil(length)), int(math.Ceil(thickness)), fill)
+  if text := renderText(label, color.White, int(2*(distance-thickness/2)*0.85), int(thickness*0.7)); text != nil {
+    textWidth, textHeight := getDimens(&text)
+    offset := image.Pt((strip.Rect.Dx()-textWidth)/2, (strip.Rect.Dy()-textHeight)/2)
+    draw.Draw(strip, text.Bounds().Add(offset), text, image.Point{}, draw.Over)
+  }
+
+  flips := ribbonCorners[corner]
+  const samples = 4
+  for py := 0; py < out.Rect.Dy(); py++ {
+    for px := 0; px < out.Rect.Dx(); px++ {
+      var r, g, b, a float64
+      for sy := 0; sy < samples; sy++ {
+        for sx := 0; sx < samples; sx++ {
+          x := float64(px) + (float64(sx)+0.5)/samples - origin
+          y := float64(py) + (float64(sy)+0.5)/samples - origin
+          if flips[0] {
+            x = size - x
+          }
+          if flips[1] {
+            y = size - y
+          }
+          along := (x-y)/math.Sqrt2 + length/2
+          across := (x+y)/math.Sqrt2 - distance + thickness/2
+          if flips[0] {
+            along = length - along
+          }
+          if flips[1] {
+            across = thickness - across
+          }
+          if x < 0 || y < 0 || along < 0 || across < 0 || along >= length || across >= thickness {
+            continue
+          }
+          c := strip.NRGBAAt(int(along), int(across))
+          alpha := float64(c.A) / 255
+          r, g, b, a = r+float64(c.R)*alpha, g+float64(c.G)*alpha, b+float64(c.B)*alpha, a+alpha
+        }
+      }
+      if a == 0 {
+        continue
+      }
+      coverage := a / (samples * samples)
+      base := out.NRGBAAt(px, py)
+      blend := func(over float64, under uint8) uint8 {
+        return uint8(math.Round(over/a*coverage + float64(under)*(1-coverage)))
+      }
+      out.SetNRGBA(px, py, color.NRGBA{blend(r, base.R), blend(g, base.G), blend(b, base.B), uint8(math.Round(255*coverage + float64(base.A)*(1-coverage)))})
+    }
+  }
+  return out
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+  "fmt"
+  "log"
+  "github.com/spf13/cobra"
+)
+
+var rmForce bool
+
+var rmCmd = &cobra.Command{
+  Use: "rm <name>...",
+  Short: "Remove drawables from every density folder.",
+  Long: `Remove drawables from every density folder.
+
+Every density and qualifier folder of every res folder is searched for the
+named drawables, and the files found are listed and removed after asking. A
+name like mipmap/ic_launcher removes only that type. Removed files are always
+backed up, so andy undo brings them back.
+
+  andy rm ic_old_logo
+  andy rm ic_old_logo ic_old_banner --force`,
+  Args: cobra.MinimumNArgs(1),
+  Run: func(cmd *cobra.Command, args []string) {
+    resFolders, err := guessResFolders()
+    if err != nil { log.Fatal(err) }
+    files, err := scanResFiles(resFolders)
+    if err != nil { log.Fatal(err) }
+
+    var remove []resFile
+    for _, name := range args {
+      named := filesNamed(files, name)
+      if len(named) == 0 {
+        log.Fatalf("no drawable named %s in %v", name, resFolders)
+      }
+      remove = append(remove, named...)
+    }
+    for _, file := range remove {
+      fmt.Printf("  %s\n", file.Path)
+    }
+    if !rmForce && !confirm(fmt.Sprintf("remove these %d files?", len(remove))) {
+      log.Fatal("nothing removed, pass --force to remove without asking.")
+    }
+    for _, file := range remove {
+      if err := removeFile(file.Path); err != nil { log.Fatal(err) }
+    }
+    fmt.Printf("%s %d files\n", green("rm"), len(remove))
+  },
+}
+
+func init() {
+  rmCmd.Flags().BoolVar(&rmForce, "force", false, "remove without asking")
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+  "encoding/json"
+  "fmt"
+  "log"
+  "os"
+  "sort"
+  "strings"
+  "text/tabwriter"
+  "github.com/spf13/cobra"
+)
+
+var (
+  scalesExamples []float64
+  scalesOutput string
+)
+
+var scalesCmd = &cobra.Command{
+  Use: "scales",
+  Short: "Print every density bucket with its dpi, scale factor and example sizes.",
+  Run: func(cmd *cobra.Command, args []string) {
+    targets := scaleTargets()
+    switch scalesOutput {
+    case "table":
+      printScalesTable(targets)
+    case "json":
+      if err := printScalesJSON(targets); err != nil { log.Fatal(err) }
+    default:
+      log.Fatalf("unknown output format %q, expected table or json", scalesOutput)
+    }
+  },
+}
+
+func init() {
+  scalesCmd.Flags().Float64SliceVar(&scalesExamples, "dp", []float64{1, 8, 48}, "dp sizes to show in px for each density")
+  scalesCmd.Flags().StringVar(&scalesOutput, "output", "table", "output format: table or json")
+}
+
+// scaleTargets is every Android density bucket plus any configured ones,
+// lowest first.
+func scaleTargets() (targets []densityTarget) {
+  seen := map[dpi]bool{}
+  for name, androidDpi := range namedDensities {
+    targets = append(targets, densityTarget{Label: name, Density: dpi(androidDpi / 40)})
+    seen[dpi(androidDpi / 40)] = true
+  }
+  for _, density := range ascendingDensityList {
+    if !seen[density] {
+      targets = append(targets, densityTarget{Label: densityToCanonical[density], Density: density})
+    }
+  }
+  sort.Slice(targets, func(i, j int) bool { return targets[i].Density < targets[j].Density })
+  return
+}
+
+func printScalesTable(targets []densityTarget) {
+  writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+  header := []string{"bucket", "dpi", "scale"}
+  for _, dp := range scalesExamples {
+    header = append(header, fmt.Sprintf("%gdp", dp))
+  }
+  fmt.Fprintln(writer, strings.Join(header, "\t") + "\t")
+  for _, target := range targets {
+    row := []string{target.Label, fmt.Sprintf("%g", float64(target.Density) * 40), fmt.Sprintf("%gx", roundTo(float64(target.Density) / MDPI, 4))}
+    for _, dp := range scalesExamples {
+      row = append(row, formatUnit(dp, target.Density, "px"))
+    }
+    fmt.Fprintln(writer, strings.Join(row, "\t") + "\t")
+  }
+  writer.Flush()
+}
+
+type scaleJSON struct {
+  Density string `json:"density"`
+  Dpi float64 `json:"dpi"`
+  Scale float64 `json:"scale"`
+  Px map[string]float64 `json:"px"`
+}
+
+func printScalesJSON(targets []densityTarget) error {
+  var scales []scaleJSON
+  for _, target := range targets {
+    px := map[string]float64{}
+    for _, dp := range scalesExamples {
+      px[fmt.Sprintf("%gdp", dp)] = roundTo(unitValue(dp, target.Density, "px"), 2)
+    }
+    scales = append(scales, scaleJSON{Density: target.Label, Dpi: float64(target.Density) * 40, Scale: roundTo(float64(target.Density) / MDPI, 4), Px: px})
+  }
+  encoder := json.NewEncoder(os.Stdout)
+  encoder.SetIndent("", "  ")
+  return encoder.Encode(scales)
+}
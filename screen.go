@@ -0,0 +1,255 @@
+package main
+
+import (
+  "fmt"
+  "log"
+  "math"
+  "os"
+  "regexp"
+  "sort"
+  "strconv"
+  "strings"
+  "github.com/spf13/cobra"
+)
+
+// screen is a device configuration: its resolution in px and its density.
+type screen struct {
+  Width int
+  Height int
+  Density dpi
+}
+
+func (s screen) WidthDp() int {
+  return int(float64(s.Width) * MDPI / float64(s.Density))
+}
+
+func (s screen) HeightDp() int {
+  return int(float64(s.Height) * MDPI / float64(s.Density))
+}
+
+func (s screen) SmallestWidthDp() int {
+  if s.WidthDp() < s.HeightDp() {
+    return s.WidthDp()
+  }
+  return s.HeightDp()
+}
+
+func (s screen) Orientation() string {
+  if s.Width > s.Height {
+    return "land"
+  }
+  return "port"
+}
+
+// SizeClass is the legacy small/normal/large/xlarge screen size qualifier.
+func (s screen) SizeClass() string {
+  long, short := s.HeightDp(), s.WidthDp()
+  if short > long {
+    long, short = short, long
+  }
+  switch {
+  case long >= 960 && short >= 720:
+    return "xlarge"
+  case long >= 640 && short >= 480:
+    return "large"
+  case long >= 470 && short >= 320:
+    return "normal"
+  }
+  return "small"
+}
+
+// windowClass is the Jetpack window size class for a width or height in dp.
+func windowClass(dp int, medium int, expanded int) string {
+  switch {
+  case dp >= expanded:
+    return "expanded"
+  case dp >= medium:
+    return "medium"
+  }
+  return "compact"
+}
+
+var screenCmd = &cobra.Command{
+  Use: "screen [WxH @ density]",
+  Short: "Show the dp size, qualifiers and resource folders a screen configuration picks.",
+  Long: `Show the dp size, qualifiers and resource folders a screen configuration picks.
+
+  andy screen 1080x2400 @ 420dpi
+  andy screen 1600x2560@xhdpi
+  andy screen --device "galaxy s23"`,
+  Run: func(cmd *cobra.Command, args []string) {
+    var s screen
+    if screenDevice != "" {
+      d, err := findDevice(screenDevice)
+      if err != nil { log.Fatal(err) }
+      s = d.Screen
+      fmt.Printf("%s %s (%dx%dpx @ %gdpi)\n", green("screen"), d.Name, s.Width, s.Height, float64(s.Density) * 40)
+    } else {
+      var err error
+      s, err = parseScreen(strings.Join(args, ""))
+      if err != nil { log.Fatal(err) }
+      fmt.Printf("%s %dx%dpx @ %gdpi\n", green("screen"), s.Width, s.Height, float64(s.Density) * 40)
+    }
+    fmt.Printf("  %14s: %dx%ddp (%s)\n", "size", s.WidthDp(), s.HeightDp(), s.Orientation())
+    fmt.Printf("  %14s: sw%ddp\n", "smallest width", s.SmallestWidthDp())
+    fmt.Printf("  %14s: sw%ddp w%ddp h%ddp %s %s\n", "qualifiers", s.SmallestWidthDp(), s.WidthDp(), s.HeightDp(), s.Orientation(), s.SizeClass())
+    fmt.Printf("  %14s: %s width, %s height\n", "window class", windowClass(s.WidthDp(), 600, 840), windowClass(s.HeightDp(), 480, 900))
+    bucket := screenBucket(s.Density)
+    fmt.Printf("  %14s: %s (assets scaled by %g)\n", "density", densityToCanonical[bucket], math.Round(float64(s.Density)/float64(bucket)*1000) / 1000)
+
+    resFolders, err := guessResFolders()
+    if err != nil {
+      return
+    }
+    for _, resFolder := range resFolders {
+      picks, err := screenFolders(resFolder, s)
+      if err != nil { log.Fatal(err) }
+      if len(picks) == 0 {
+        continue
+      }
+      fmt.Printf("%s %s\n", green("res"), tryGetAbsPath(resFolder))
+      var types []string
+      for resType := range picks {
+        types = append(types, resType)
+      }
+      sort.Strings(types)
+      for _, resType := range types {
+        fmt.Printf("  %s %s\n", green("->"), picks[resType])
+      }
+    }
+  },
+}
+
+var screenDevice string
+
+func init() {
+  screenCmd.Flags().StringVar(&screenDevice, "device", "", "use a known device's screen (e.g. pixel8, \"galaxy s23\")")
+}
+
+var screenRegex = regexp.MustCompile(`^(\d+)x(\d+)(?:px)?@(.+)$`)
+
+func parseScreen(s string) (screen, error) {
+  match := screenRegex.FindStringSubmatch(strings.ToLower(strings.ReplaceAll(s, " ", "")))
+  if match == nil {
+    return screen{}, fmt.Errorf("can't read screen %q, ex: 1080x2400 @ 420dpi", s)
+  }
+  width, _ := strconv.Atoi(match[1])
+  height, _ := strconv.Atoi(match[2])
+  density, err := parseDensity(match[3])
+  if err != nil {
+    return screen{}, err
+  }
+  if width == 0 || height == 0 {
+    return screen{}, fmt.Errorf("can't read screen %q, ex: 1080x2400 @ 420dpi", s)
+  }
+  return screen{Width: width, Height: height, Density: density}, nil
+}
+
+// screenBucket is the configured density a device loads bitmaps from: the
+// smallest bucket at or above its density, so assets are only scaled down.
+func screenBucket(density dpi) dpi {
+  for _, bucket := range ascendingDensityList {
+    if bucket >= density {
+      return bucket
+    }
+  }
+  return ascendingDensityList[len(ascendingDensityList)-1]
+}
+
+// screenFolders picks, for each resource type in resFolder, the folder s
+// would load from on a current Android version. Folders with qualifiers
+// other than the screen ones (sw, w, h, orientation, size, density and
+// platform version) aren't considered.
+func screenFolders(resFolder string, s screen) (map[string]string, error) {
+  entries, err := os.ReadDir(resFolder)
+  if err != nil {
+    return nil, err
+  }
+  picks := map[string]string{}
+  ranks := map[string][]int{}
+  for _, entry := range entries {
+    if !entry.IsDir() {
+      continue
+    }
+    resType, rank, ok := rankFolder(entry.Name(), s)
+    if !ok {
+      continue
+    }
+    if best, seen := ranks[resType]; !seen || betterRank(rank, best) {
+      picks[resType], ranks[resType] = entry.Name(), rank
+    }
+  }
+  return picks, nil
+}
+
+var screenSizes = []string{"small", "normal", "large", "xlarge"}
+
+// rankFolder reports whether folder fits s, and how specific a match it is
+// in Android's qualifier precedence order.
+func rankFolder(folder string, s screen) (resType string, rank []int, ok bool) {
+  parts := strings.Split(folder, "-")
+  sw, w, h, size, orientation, version := 0, 0, 0, 0, 0, 0
+  density := densityRank(MDPI, s.Density)
+  for _, qualifier := range parts[1:] {
+    if value, isDp := dpQualifier(qualifier, "sw"); isDp {
+      if value > s.SmallestWidthDp() { return }
+      sw = value
+    } else if value, isDp := dpQualifier(qualifier, "w"); isDp {
+      if value > s.WidthDp() { return }
+      w = value
+    } else if value, isDp := dpQualifier(qualifier, "h"); isDp {
+      if value > s.HeightDp() { return }
+      h = value
+    } else if qualifier == "port" || qualifier == "land" {
+      if qualifier != s.Orientation() { return }
+      orientation = 1
+    } else if index := indexOf(screenSizes, qualifier); index >= 0 {
+      if index > indexOf(screenSizes, s.SizeClass()) { return }
+      size = index + 1
+    } else if value, err := strconv.Atoi(strings.TrimPrefix(qualifier, "v")); err == nil && strings.HasPrefix(qualifier, "v") {
+      version = value
+    } else if qualifier == "anydpi" {
+      density = math.MaxInt32
+    } else if androidDpi, isDensity := namedDensities[qualifier]; isDensity {
+      density = densityRank(dpi(androidDpi / 40), s.Density)
+    } else {
+      return
+    }
+  }
+  return parts[0], []int{sw, w, h, size, orientation, density, version}, true
+}
+
+// densityRank prefers the closest density above the device's, then the
+// closest below it.
+func densityRank(folder dpi, device dpi) int {
+  if folder >= device {
+    return 1000000 - int(folder * 40)
+  }
+  return int(folder * 40) - 1000000
+}
+
+func dpQualifier(qualifier string, prefix string) (int, bool) {
+  if !strings.HasPrefix(qualifier, prefix) || !strings.HasSuffix(qualifier, "dp") {
+    return 0, false
+  }
+  value, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(qualifier, prefix), "dp"))
+  return value, err == nil
+}
+
+func betterRank(rank []int, than []int) bool {
+  for i := range rank {
+    if rank[i] != than[i] {
+      return rank[i] > than[i]
+    }
+  }
+  return false
+}
+
+func indexOf(list []string, s string) int {
+  for i, item := range list {
+    if item == s {
+      return i
+    }
+  }
+  return -1
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+  "archive/zip"
+  "bytes"
+  "encoding/json"
+  "fmt"
+  "image"
+  "io"
+  "log"
+  "net"
+  "net/http"
+  "path"
+  "strconv"
+  "strings"
+  "time"
+  "github.com/spf13/cobra"
+)
+
+// maxUpload is the largest master andy serve accepts, and maxPixels the most
+// pixels it may decode to, as a small png can claim to be huge.
+const (
+  maxUpload = 64 << 20
+  maxPixels = 8192 * 8192
+)
+
+// serveReadTimeout gives a maxUpload master two minutes to arrive, about
+// 512KB/s, and serveWriteTimeout, which counts from the request's headers,
+// the time to resize it and send the zip back on top.
+const (
+  serveHeaderTimeout = 10 * time.Second
+  serveReadTimeout = 2 * time.Minute
+  serveWriteTimeout = 4 * time.Minute
+)
+
+var (
+  serveHost string
+  servePort int
+  serveGRPCPort int
+)
+
+var serveCmd = &cobra.Command{
+  Use: "serve",
+  Short: "Run andy as an HTTP service for web tools and design plugins.",
+  Long: `Run andy as an HTTP service for web tools and design plugins.
+
+  POST /dpi?density=xxxhdpi&name=ic_logo[&format=webp]
+    The request body is the master image. The response is a zip of it
+    resized for its density and every lower one, as drawable-*/ic_logo.png.
+    A name ending in .9 is resized as a nine-patch.
+  GET /convert?value=24dp&value=96px&at=xxhdpi[&to=px,dp]
+    Converts values like andy convert --output json does.
+  POST /check?density=xxxhdpi[&name=ic_logo]
+    Checks the master in the request body like andy check <image> does and
+    responds with its findings like andy check --output json.
+
+Errors are responded to with a 400 and the message as plain text. It only
+listens on localhost unless --host says otherwise.
+
+--grpc-port also serves the same operations over gRPC, as the Andy service
+in andypb/andy.proto, with masters and generated images streamed in chunks.
+--port 0 turns HTTP off.
+
+  andy serve --port 7345
+  andy serve --port 0 --grpc-port 7346
+  curl --data-binary @logo.png 'localhost:7345/dpi?density=xxxhdpi&name=ic_logo' -o ic_logo.zip`,
+  Args: cobra.NoArgs,
+  Run: func(cmd *cobra.Command, args []string) {
+    if _, ok := roundingFuncs[outputOptions.Rounding]; !ok {
+      log.Fatalf("unknown rounding policy %q", outputOptions.Rounding)
+    }
+    if servePort == 0 && serveGRPCPort == 0 {
+      log.Fatal("--port 0 needs a --grpc-port, there's nothing to serve.")
+    }
+    errs := make(chan error)
+    if serveGRPCPort != 0 {
+      listener, err := net.Listen("tcp", net.JoinHostPort(serveHost, strconv.Itoa(serveGRPCPort)))
+      if err != nil { log.Fatal(err) }
+      fmt.Printf("%s grpc listening on %s\n", green("serve"), listener.Addr())
+      go func() { errs <- newGRPCServer().Serve(listener) }()
+    }
+    if servePort != 0 {
+      mux := http.NewServeMux()
+      mux.HandleFunc("/dpi", serveHandler("POST", serveDpi))
+      mux.HandleFunc("/convert", serveHandler("GET", serveConvert))
+      mux.HandleFunc("/check", serveHandler("POST", serveCheck))
+      addr := net.JoinHostPort(serveHost, strconv.Itoa(servePort))
+      fmt.Printf("%s listening on http://%s\n", green("serve"), addr)
+      server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: serveHeaderTimeout, ReadTimeout: serveReadTimeout, WriteTimeout: serveWriteTimeout}
+      go func() { errs <- server.ListenAndServe() }()
+    }
+    log.Fatal(<-errs)
+  },
+}
+
+func init() {
+  serveCmd.Flags().StringVar(&serveHost, "host", "localhost", "address to listen on, 0.0.0.0 for every interface")
+  serveCmd.Flags().IntVar(&servePort, "port", 7345, "port to listen on for HTTP, 0 for none")
+  serveCmd.Flags().IntVar(&serveGRPCPort, "grpc-port", 0, "port to also listen on for gRPC")
+  serveCmd.Flags().StringVar(&outputOptions.Rounding, "round", "floor", "how to round scaled pixel dimensions: ceil, floor, nearest or even")
+}
+
+// serveHandler only lets method through to handle, and responds to the error
+// handle returns.
+func serveHandler(method string, handle func(w http.ResponseWriter, r *http.Request) error) http.HandlerFunc {
+  return func(w http.ResponseWriter, r *http.Request) {
+    if r.Method != method {
+      w.Header().Set("Allow", method)
+      http.Error(w, fmt.Sprintf("%s only takes %s", r.URL.Path, method), http.StatusMethodNotAllowed)
+      return
+    }
+    r.Body = http.MaxBytesReader(w, r.Body, maxUpload)
+    if err := handle(w, r); err != nil {
+      printf("  %s %s %s: %v\n", red("fail"), r.Method, r.URL.Path, err)
+      http.Error(w, err.Error(), http.StatusBadRequest)
+      return
+    }
+    printf("  %s %s %s\n", green("ok"), r.Method, r.URL.RequestURI())
+  }
+}
+
+// uploadedMaster reads the image in the request body, drawn at the density
+// in the query.
+func uploadedMaster(r *http.Request) (data []byte, density dpi, err error) {
+  param := r.URL.Query().Get("density")
+  if param == "" {
+    return nil, 0, fmt.Errorf("need the density the image was drawn at, ex: ?density=xxxhdpi")
+  }
+  if density, err = parseDensity(param); err != nil {
+    return
+  }
+  if data, err = io.ReadAll(r.Body); err != nil {
+    return nil, 0, err
+  }
+  return
+}
+
+// uploadConfig reads the size of an uploaded image without decoding it,
+// refusing images with more than maxPixels.
+func uploadConfig(data []byte) (image.Config, error) {
+  config, _, err := image.DecodeConfig(bytes.NewReader(data))
+  if err != nil {
+    return config, fmt.Errorf("decoding the image: %v", err)
+  }
+  if int64(config.Width) * int64(config.Height) > maxPixels {
+    return config, fmt.Errorf("the image is %dx%d, masters can be at most %d megapixels", config.Width, config.Height, maxPixels >> 20)
+  }
+  return config, nil
+}
+
+// decodeUpload decodes an uploaded image, once uploadConfig says it isn't
+// too big to.
+func decodeUpload(data []byte) (image.Image, string, error) {
+  if _, err := uploadConfig(data); err != nil {
+    return nil, "", err
+  }
+  img, format, err := image.Decode(bytes.NewReader(data))
+  if err != nil {
+    return nil, "", fmt.Errorf("decoding the image: %v", err)
+  }
+  return img, format, nil
+}
+
+// densityImage is a generated density of an uploaded master.
+type densityImage struct {
+  Path string
+  Density dpi
+  Size image.Point
+  Data []byte
+}
+
+// densitySet resizes img, drawn at density from, for from and every lower
+// density, encoded in format or the source's. name ends in .9 for
+// nine-patches.
+func densitySet(img image.Image, sourceFormat string, from dpi, name string, format string) ([]densityImage, error) {
+  if name == "" {
+    name = "image"
+  }
+  ninePatch := strings.HasSuffix(name, ".9")
+  if !fileResourceRegex.MatchString(strings.TrimSuffix(name, ".9")) {
+    return nil, fmt.Errorf("%q isn't a valid resource name", name)
+  }
+  if format == "" {
+    format = sourceFormat
+  }
+  if _, ok := formatExtensions[format]; !ok {
+    return nil, fmt.Errorf("unknown format %q, expected png, webp or jpeg", format)
+  }
+  if ninePatch {
+    // aapt only takes png nine-patches
+    format = "png"
+  }
+
+  var images []densityImage
+  width, height := getDimens(&img)
+  for _, density := range ascendingDensityList {
+    if density > from {
+      continue
+    }
+    var resized image.Image
+    if ninePatch && density != from {
+      targetWidth, _ := scaleDimension(width - 2, from, density)
+      targetHeight, _ := scaleDimension(height - 2, from, density)
+      resized = resizeNinePatch(img, targetWidth, targetHeight)
+    } else {
+      resized = scaleImage(img, from, density)
+    }
+    var buf bytes.Buffer
+    if err := encodeImage(&buf, resized, format); err != nil {
+      return nil, err
+    }
+    file := path.Join(densityToFolder[density], name + formatExtensions[format])
+    images = append(images, densityImage{Path: file, Density: density, Size: resized.Bounds().Size(), Data: buf.Bytes()})
+  }
+  return images, nil
+}
+
+func serveDpi(w http.ResponseWriter, r *http.Request) error {
+  data, from, err := uploadedMaster(r)
+  if err != nil {
+    return err
+  }
+  img, format, err := decodeUpload(data)
+  if err != nil {
+    return err
+  }
+  name := r.URL.Query().Get("name")
+  images, err := densitySet(img, format, from, name, r.URL.Query().Get("format"))
+  if err != nil {
+    return err
+  }
+  var archive bytes.Buffer
+  zipped := zip.NewWriter(&archive)
+  for _, generated := range images {
+    file, err := zipped.Create(generated.Path)
+    if err != nil {
+      return err
+    }
+    if _, err := file.Write(generated.Data); err != nil {
+      return err
+    }
+  }
+  if err := zipped.Close(); err != nil {
+    return err
+  }
+  if name == "" {
+    name = "image"
+  }
+  w.Header().Set("Content-Type", "application/zip")
+  w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", strings.TrimSuffix(name, ".9") + ".zip"))
+  _, err = w.Write(archive.Bytes())
+  return err
+}
+
+// convertValues converts inputs like andy convert --output json, at the at
+// densities (or the one px inputs were measured at) in units, px if empty.
+func convertValues(inputs []string, at []string, units []string) ([]conversionJSON, error) {
+  if len(inputs) == 0 {
+    return nil, fmt.Errorf("need one or more values to convert, ex: 30dp")
+  }
+  if len(units) == 0 {
+    units = []string{"px"}
+  }
+  for _, u := range units {
+    if _, ok := dpPerUnit[u]; !ok && u != "px" && u != "dp" {
+      return nil, fmt.Errorf("unknown output unit %q, expected px, dp, mm, in or pt", u)
+    }
+  }
+  var source dpi
+  if len(at) == 1 {
+    var err error
+    if source, err = parseDensity(at[0]); err != nil {
+      return nil, err
+    }
+  }
+  var dpValues []float64
+  hasPx := false
+  for _, input := range inputs {
+    value, unit, err := parseMeasurement(input)
+    if err != nil {
+      return nil, err
+    }
+    dpValue, err := convertDp(value, unit, source)
+    if err != nil {
+      return nil, err
+    }
+    dpValues = append(dpValues, dpValue)
+    hasPx = hasPx || unit == "px"
+  }
+  // px values are shown at every bucket, like andy convert does
+  if hasPx {
+    at = nil
+  }
+  targets, err := parseDensityTargets(at)
+  if err != nil {
+    return nil, err
+  }
+  return conversionsJSON(inputs, dpValues, targets, units), nil
+}
+
+func serveConvert(w http.ResponseWriter, r *http.Request) error {
+  query := r.URL.Query()
+  var at, units []string
+  if query.Get("at") != "" {
+    at = strings.Split(query.Get("at"), ",")
+  }
+  if query.Get("to") != "" {
+    units = strings.Split(query.Get("to"), ",")
+  }
+  conversions, err := convertValues(query["value"], at, units)
+  if err != nil {
+    return err
+  }
+  return writeJSON(w, conversions)
+}
+
+// checkMaster checks an uploaded master of config's size like andy check
+// <image> does. Only its size is checked, so it's never decoded.
+func checkMaster(config image.Config, density dpi, name string) findingsJSON {
+  if name == "" {
+    name = "image"
+  }
+  findings := []Finding{}
+  for _, problem := range gridProblems(config.Width, config.Height, density) {
+    findings = append(findings, Finding{Check: "grid", Path: name, Message: problem})
+  }
+  return findingsJSON{Checked: 1, Findings: findings}
+}
+
+func serveCheck(w http.ResponseWriter, r *http.Request) error {
+  data, density, err := uploadedMaster(r)
+  if err != nil {
+    return err
+  }
+  config, err := uploadConfig(data)
+  if err != nil {
+    return err
+  }
+  return writeJSON(w, checkMaster(config, density, r.URL.Query().Get("name")))
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) error {
+  data, err := json.MarshalIndent(value, "", "  ")
+  if err != nil {
+    return err
+  }
+  w.Header().Set("Content-Type", "application/json")
+  _, err = w.Write(append(data, '\n'))
+  return err
+}
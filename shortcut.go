@@ -0,0 +1,121 @@
+package main
+
+import (
+  "fmt"
+  "image"
+  "image/draw"
+  "log"
+  "path/filepath"
+  "strings"
+  "github.com/spf13/cobra"
+)
+
+const (
+  shortcutIconDp = 48
+  shortcutCircleDp = 44
+  shortcutGlyphDp = 24
+)
+
+const shortcutsXML = `<?xml version="1.0" encoding="utf-8"?>
+<shortcuts xmlns:android="http://schemas.android.com/apk/res/android">
+%s</shortcuts>
+`
+
+const shortcutXML = `    <shortcut
+        android:shortcutId="%s"
+        android:enabled="true"
+        android:icon="@drawable/%s"
+        android:shortcutShortLabel="@string/%s_short_label">
+        <intent
+            android:action="android.intent.action.VIEW"
+            android:targetPackage="%s"
+            android:targetClass="%s" />
+    </shortcut>
+`
+
+var (
+  shortcutBackground string
+  shortcutXMLStub bool
+  shortcutPackage string
+  shortcutActivity string
+  shortcutSourceSets []string
+)
+
+var shortcutCmd = &cobra.Command{
+  Use: "shortcut [glyphs]",
+  Short: "Generate static app shortcut icons (24dp glyph in a 48dp icon) for every density.",
+  Run: func(cmd *cobra.Command, args []string) {
+    if len(args) < 1 {
+      log.Fatal("need one or more filenames.")
+    }
+    if err := checkOutputOptions(); err != nil {
+      log.Fatal(err)
+    }
+    if !cmd.Flags().Changed("source-set") {
+      shortcutSourceSets = config.SourceSets
+    }
+    if shortcutXMLStub && shortcutPackage == "" {
+      log.Fatal("--xml needs --package.")
+    }
+    if shortcutActivity == "" {
+      shortcutActivity = shortcutPackage + ".MainActivity"
+    }
+    var background *image.NRGBA
+    if shortcutBackground != "" {
+      c, err := parseHexColor(shortcutBackground)
+      if err != nil { log.Fatal(err) }
+      background = circleMask(solidImage(shortcutCircleDp*4, shortcutCircleDp*4, c), 1)
+    }
+
+    resFolder, err := defaultResFolder()
+    if err != nil { log.Fatal(err) }
+    targets := targetResFolders(resFolder, shortcutSourceSets)
+
+    var entries []string
+    for _, arg := range args {
+      glyph, _, err := decodeImageFile(arg)
+      if err != nil { log.Fatal(err) }
+      fmt.Printf("%s %s\n", green("from"), arg)
+
+      icon := fitCentered(glyph, shortcutIconDp, shortcutGlyphDp, 4)
+      if background != nil {
+        backed := image.NewNRGBA(icon.Bounds())
+        offset := image.Pt((shortcutIconDp-shortcutCircleDp)*2, (shortcutIconDp-shortcutCircleDp)*2)
+        draw.Draw(backed, background.Bounds().Add(offset), background, image.Point{}, draw.Src)
+        draw.Draw(backed, backed.Bounds(), icon, image.Point{}, draw.Over)
+        icon = backed
+      }
+
+      filename := withFormatExtension(filepath.Base(arg), "png")
+      name := strings.TrimSuffix(filename, ".png")
+      for _, target := range targets {
+        if err := writeDensitySet(target, "drawable", filename, icon, shortcutIconDp, shortcutIconDp); err != nil {
+          log.Fatal(err)
+        }
+      }
+      id := strings.TrimPrefix(strings.TrimPrefix(name, "ic_"), "shortcut_")
+      entries = append(entries, fmt.Sprintf(shortcutXML, id, name, id, shortcutPackage, shortcutActivity))
+    }
+
+    if shortcutXMLStub {
+      for _, target := range targets {
+        path := filepath.Join(target, "xml", "shortcuts.xml")
+        if skipExisting(path) {
+          continue
+        }
+        if err := writeFile(path, []byte(fmt.Sprintf(shortcutsXML, strings.Join(entries, "")))); err != nil {
+          log.Fatal(err)
+        }
+      }
+    }
+  },
+}
+
+func init() {
+  addOutputFlags(shortcutCmd)
+  shortcutCmd.Flags().StringVar(&shortcutBackground, "background", "", "color of the 44dp circle behind the glyph")
+  shortcutCmd.Flags().BoolVar(&shortcutXMLStub, "xml", false, "also write an xml/shortcuts.xml stub referencing the icons")
+  shortcutCmd.Flags().StringVar(&shortcutPackage, "package", "", "target package for the shortcuts.xml intents")
+  shortcutCmd.Flags().StringVar(&shortcutActivity, "activity", "", "target activity class for the shortcuts.xml intents (default <package>.MainActivity)")
+  shortcutCmd.Flags().StringSliceVarP(&shortcutSourceSets, "source-set", "s", nil, "source sets to write generated assets into (e.g. main,paid or all)")
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+  "fmt"
+  "image"
+  "log"
+  "math"
+  "os"
+  "path/filepath"
+  "strings"
+  "github.com/spf13/cobra"
+)
+
+var sizeDensity string
+
+var sizeCmd = &cobra.Command{
+  Use: "size [images]",
+  Short: "Print an image's dp size and the px it would take at every density.",
+  Long: `Print an image's dp size and the px it would take at every density.
+
+The density comes from the image's folder, or --at for images outside a res
+folder. Sizes that don't come out to whole pixels are flagged.
+
+  andy size res/drawable-xxhdpi/ic_hero.png
+  andy size ~/Downloads/hero@3x.png --at xxhdpi`,
+  Run: func(cmd *cobra.Command, args []string) {
+    if len(args) < 1 {
+      log.Fatal("need one or more images.")
+    }
+    for _, path := range args {
+      if err := printSize(path); err != nil {
+        log.Fatal(err)
+      }
+    }
+  },
+}
+
+func init() {
+  sizeCmd.Flags().StringVar(&sizeDensity, "at", "", "density of the images, instead of reading it from their folder")
+}
+
+func printSize(path string) error {
+  density, err := imageDensity(path, sizeDensity)
+  if err != nil {
+    return err
+  }
+  file, err := os.Open(path)
+  if err != nil {
+    return err
+  }
+  defer file.Close()
+  bounds, _, err := image.DecodeConfig(file)
+  if err != nil {
+    return fmt.Errorf("%s: %v", path, err)
+  }
+
+  widthDp, heightDp := float64(bounds.Width) * MDPI / float64(density), float64(bounds.Height) * MDPI / float64(density)
+  fmt.Printf("%s %s\n", green("size"), path)
+  fmt.Printf("  %8s: %dx%dpx = %sx%sdp\n", densityLabel(density), bounds.Width, bounds.Height, formatSize(widthDp), formatSize(heightDp))
+  for _, bucket := range ascendingDensityList {
+    if bucket == density {
+      continue
+    }
+    width, height := widthDp * float64(bucket) / MDPI, heightDp * float64(bucket) / MDPI
+    fmt.Printf("  %8s: %sx%spx", densityToCanonical[bucket], formatSize(width), formatSize(height))
+    if !isWhole(width) || !isWhole(height) {
+      fmt.Printf(" %s", yellow("not whole pixels"))
+    }
+    fmt.Println()
+  }
+  return nil
+}
+
+// imageDensity reads the density of an image from at, if given, or the
+// qualifiers of its folder, e.g. drawable-xxhdpi or mipmap-night-xhdpi.
+func imageDensity(path string, at string) (dpi, error) {
+  if at != "" {
+    return parseDensity(at)
+  }
+  if density, err := extractDensity(tryGetAbsPath(path)); err == nil {
+    return density, nil
+  }
+  for _, qualifier := range strings.Split(filepath.Base(filepath.Dir(tryGetAbsPath(path))), "-")[1:] {
+    if density, err := parseDensity(qualifier); err == nil && strings.HasSuffix(qualifier, "dpi") {
+      return density, nil
+    }
+  }
+  return 0, fmt.Errorf("%s: no density found in its folder, pass --at", path)
+}
+
+func densityLabel(density dpi) string {
+  if canonical, ok := densityToCanonical[density]; ok {
+    return canonical
+  }
+  return fmt.Sprintf("%gdpi", float64(density) * 40)
+}
+
+func isWhole(value float64) bool {
+  return math.Abs(value - math.Round(value)) < 0.001
+}
+
+// formatSize prints whole values as integers and the rest to one decimal.
+func formatSize(value float64) string {
+  if isWhole(value) {
+    return fmt.Sprintf("%d", int(math.Round(value)))
+  }
+  return fmt.Sprintf("%.1f", value)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+  "fmt"
+  "image"
+  "image/draw"
+  "log"
+  "math"
+  "path/filepath"
+  "strings"
+  "github.com/nfnt/resize"
+  "github.com/spf13/cobra"
+)
+
+// Android 12 splash screen icon sizes: the whole icon and the circle its
+// content must fit in, with and without an icon background.
+const (
+  splashIconDp = 288
+  splashIconContentDp = 192
+  splashBackedIconDp = 240
+  splashBackedIconContentDp = 160
+)
+
+const splashThemeXML = `<?xml version="1.0" encoding="utf-8"?>
+<resources>
+    <style name="%s" parent="%s">
+%s    </style>
+</resources>
+`
+
+var (
+  splashName string
+  splashIconBackground string
+  splashBackground string
+  splashTheme string
+  splashParentTheme string
+  splashSourceSets []string
+)
+
+var splashCmd = &cobra.Command{
+  Use: "splash [logo]",
+  Short: "Generate Android 12 splash screen icons for every density plus the values-v31 theme.",
+  Run: func(cmd *cobra.Command, args []string) {
+    if len(args) != 1 {
+      log.Fatal("need one logo image.")
+    }
+    if err := checkOutputOptions(); err != nil {
+      log.Fatal(err)
+    }
+    if !cmd.Flags().Changed("source-set") {
+      splashSourceSets = config.SourceSets
+    }
+    for _, c := range []string{splashIconBackground, splashBackground} {
+      if c == "" { continue }
+      if _, err := parseHexColor(c); err != nil { log.Fatal(err) }
+    }
+
+    logo, _, err := decodeImageFile(args[0])
+    if err != nil { log.Fatal(err) }
+    fmt.Printf("%s %s\n", green("from"), args[0])
+
+    iconDp, contentDp := float64(splashIconDp), float64(splashIconContentDp)
+    if splashIconBackground != "" {
+      iconDp, contentDp = splashBackedIconDp, splashBackedIconContentDp
+    }
+    icon := fitInCircle(logo, iconDp, contentDp)
+
+    resFolder, err := defaultResFolder()
+    if err != nil { log.Fatal(err) }
+    for _, target := range targetResFolders(resFolder, splashSourceSets) {
+      if err := writeDensitySet(target, "drawable", splashName + ".png", icon, iconDp, iconDp); err != nil {
+        log.Fatal(err)
+      }
+      if err := writeSplashTheme(target); err != nil {
+        log.Fatal(err)
+      }
+    }
+  },
+}
+
+func init() {
+  addOutputFlags(splashCmd)
+  splashCmd.Flags().StringVar(&splashName, "name", "splash_icon", "resource name of the splash screen icon")
+  splashCmd.Flags().StringVar(&splashIconBackground, "icon-background", "", "icon background color; uses the smaller 240dp icon size")
+  splashCmd.Flags().StringVar(&splashBackground, "background", "", "window background color of the splash screen")
+  splashCmd.Flags().StringVar(&splashTheme, "theme", "Theme.App.Starting", "name of the generated splash theme")
+  splashCmd.Flags().StringVar(&splashParentTheme, "parent-theme", "android:Theme.Material.Light.NoActionBar", "parent of the generated splash theme")
+  splashCmd.Flags().StringSliceVarP(&splashSourceSets, "source-set", "s", nil, "source sets to write generated assets into (e.g. main,paid or all)")
+}
+
+// fitInCircle centers logo on a transparent iconDp canvas, scaled so none of
+// its visible pixels fall outside the centered contentDp circle.
+func fitInCircle(logo image.Image, iconDp float64, contentDp float64) image.Image {
+  src := toNRGBA(logo)
+  width, height := src.Rect.Dx(), src.Rect.Dy()
+  cx, cy := float64(width)/2, float64(height)/2
+  radius := 0.0
+  for y := 0; y < height; y++ {
+    for x := 0; x < width; x++ {
+      if src.NRGBAAt(x, y).A > 0 {
+        radius = math.Max(radius, math.Hypot(math.Abs(float64(x)+0.5-cx)+0.5, math.Abs(float64(y)+0.5-cy)+0.5))
+      }
+    }
+  }
+  if radius == 0 {
+    radius = math.Hypot(cx, cy)
+  }
+
+  // render at 4px per dp, plenty for xxxhdpi
+  const pxPerDp = 4
+  size := int(iconDp * pxPerDp)
+  scale := contentDp * pxPerDp / 2 / radius
+  scaledWidth, scaledHeight := uint(math.Round(float64(width)*scale)), uint(math.Round(float64(height)*scale))
+  scaled := resize.Resize(scaledWidth, scaledHeight, src, resize.Lanczos3)
+
+  canvas := image.NewNRGBA(image.Rect(0, 0, size, size))
+  offset := image.Pt((size-int(scaledWidth))/2, (size-int(scaledHeight))/2)
+  draw.Draw(canvas, scaled.Bounds().Add(offset), scaled, scaled.Bounds().Min, draw.Over)
+  return canvas
+}
+
+func writeSplashTheme(resFolder string) error {
+  items := fmt.Sprintf("        <item name=\"android:windowSplashScreenAnimatedIcon\">@drawable/%s</item>\n", splashName)
+  if splashIconBackground != "" {
+    items += fmt.Sprintf("        <item name=\"android:windowSplashScreenIconBackgroundColor\">%s</item>\n", strings.ToUpper(splashIconBackground))
+  }
+  if splashBackground != "" {
+    items += fmt.Sprintf("        <item name=\"android:windowSplashScreenBackground\">%s</item>\n", strings.ToUpper(splashBackground))
+  }
+
+  path := filepath.Join(resFolder, "values-v31", splashName + "_theme.xml")
+  if skipExisting(path) {
+    return nil
+  }
+  return writeFile(path, []byte(fmt.Sprintf(splashThemeXML, splashTheme, splashParentTheme, items)))
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+  "fmt"
+  "log"
+  "os"
+  "path/filepath"
+  "regexp"
+  "strconv"
+  "strings"
+  "github.com/spf13/cobra"
+)
+
+var importSuffixCmd = &cobra.Command{
+  Use: "suffix <export folder>",
+  Short: "Import @2x and @3x style exports from Sketch or Figma.",
+  Long: `Import @2x and @3x style exports from Sketch or Figma.
+
+Every image in the folder is named after its scale, icon.png being 1x (mdpi)
+and icon@2x.png, icon@3x.png and icon@4x.png 2x, 3x and 4x. The highest
+scale of each image is copied into the matching density folder under a
+valid resource name, and every other density is generated from it.
+
+  andy import suffix ~/Downloads/export`,
+  Args: cobra.ExactArgs(1),
+  Run: func(cmd *cobra.Command, args []string) {
+    if err := checkOutputOptions(); err != nil { log.Fatal(err) }
+    images, err := scaledImages(args[0])
+    if err != nil { log.Fatal(err) }
+    if err := importImages(images); err != nil { log.Fatal(err) }
+  },
+}
+
+func init() {
+  addImportCommand(importSuffixCmd)
+}
+
+var scaleSuffixRegex = regexp.MustCompile(`@([0-9.]+)x$`)
+
+// scaledImages lists the images in dir by the density of their @Nx suffix.
+func scaledImages(dir string) (images []importedImage, err error) {
+  entries, err := os.ReadDir(dir)
+  if err != nil {
+    return nil, err
+  }
+  for _, entry := range entries {
+    ext := filepath.Ext(entry.Name())
+    if entry.IsDir() || !isBitmapExt(ext) {
+      continue
+    }
+    name := strings.TrimSuffix(entry.Name(), ext)
+    scale := 1.0
+    if match := scaleSuffixRegex.FindStringSubmatch(name); match != nil {
+      scale, err = strconv.ParseFloat(match[1], 64)
+      if err != nil {
+        return nil, fmt.Errorf("%s: %v", entry.Name(), err)
+      }
+      name = strings.TrimSuffix(name, match[0])
+    }
+    density, err := scaleDensity(scale)
+    if err != nil {
+      printf("  %s %s: %v\n", yellow("skip"), entry.Name(), err)
+      continue
+    }
+    images = append(images, importedImage{Name: name, Density: density, Path: filepath.Join(dir, entry.Name()), Ext: strings.ToLower(ext)})
+  }
+  return
+}
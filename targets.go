@@ -0,0 +1,217 @@
+package main
+
+import (
+  "fmt"
+  "image"
+  "os"
+  "path/filepath"
+  "strings"
+  "github.com/nfnt/resize"
+)
+
+// outputTarget is a platform other than Android that andy dpi can write a
+// master's densities for.
+type outputTarget struct {
+  // Out is the folder written into without --out
+  Out string
+  // Densities are written in order. If nil, every Android density up to
+  // the master's is, like for the res folder.
+  Densities []dpi
+  // Path is where the image of name at density goes under out
+  Path func(out string, name string, density dpi) string
+}
+
+var outputTargets = map[string]outputTarget{
+  "flutter": {Out: "assets", Densities: []dpi{MDPI, 2 * MDPI, 3 * MDPI}, Path: flutterPath},
+  "react-native": {Out: "assets", Densities: []dpi{MDPI, 2 * MDPI, 3 * MDPI}, Path: reactNativePath},
+  "compose": {Out: filepath.Join("src", "commonMain", "composeResources"), Path: composePath},
+}
+
+// flutterPath puts 1x images in out and the others in out/2.0x/ and such,
+// where Flutter's AssetImage looks for them.
+func flutterPath(out string, name string, density dpi) string {
+  if density == MDPI {
+    return filepath.Join(out, name)
+  }
+  return filepath.Join(out, fmt.Sprintf("%.1fx", float64(density) / MDPI), name)
+}
+
+// targetAssets are the 1x images of every target asset, for the pubspec.
+var targetAssets []string
+
+// reactNativePath names the images foo.png, foo@2x.png and foo@3x.png, which
+// require('./foo.png') picks from by screen density.
+func reactNativePath(out string, name string, density dpi) string {
+  if density == MDPI {
+    return filepath.Join(out, name)
+  }
+  ext := filepath.Ext(name)
+  return filepath.Join(out, fmt.Sprintf("%s@%gx%s", strings.TrimSuffix(name, ext), float64(density) / MDPI, ext))
+}
+
+// composeQualifiers are the density qualifiers Compose Multiplatform
+// resources understand.
+var composeQualifiers = map[string]bool{"ldpi": true, "mdpi": true, "hdpi": true, "xhdpi": true, "xxhdpi": true, "xxxhdpi": true}
+
+// composePath puts images in the drawable-<density> folders of a
+// composeResources folder, which Res.drawable picks from like Android does.
+func composePath(out string, name string, density dpi) string {
+  return filepath.Join(out, "drawable-" + densityToCanonical[density], name)
+}
+
+func checkTarget(name string) error {
+  if _, ok := outputTargets[name]; name != "android" && !ok {
+    return fmt.Errorf("unknown --target %q, expected android, flutter, react-native or compose", name)
+  }
+  return nil
+}
+
+// targetOut is the folder target writes into.
+func targetOut(target outputTarget) string {
+  if outputOptions.Out != "" {
+    return outputOptions.Out
+  }
+  return target.Out
+}
+
+func addTargetAsset(path string) {
+  outputMutex.Lock()
+  defer outputMutex.Unlock()
+  targetAssets = append(targetAssets, path)
+}
+
+// writeTarget resizes img to every density of target.
+func writeTarget(target outputTarget, info DrawableInfo, img image.Image) error {
+  if info.NinePatch {
+    return fmt.Errorf("nine-patches only work on Android")
+  }
+  out := targetOut(target)
+  filename := withFormatExtension(info.Filename, info.Format)
+  width, height := getDimens(&img)
+  densities := target.Densities
+  if densities == nil {
+    for _, density := range ascendingDensityList {
+      if density <= info.Density && composeQualifiers[densityToCanonical[density]] {
+        densities = append(densities, density)
+      }
+    }
+  }
+  for _, density := range densities {
+    path := target.Path(out, filename, density)
+    if info.Skip[density] || skipExistingOutput(info.Asset, path) {
+      continue
+    }
+    if density > info.Density {
+      printf("  %s %s is upscaled from %s\n", yellow("warn"), path, densityToCanonical[info.Density])
+    }
+    resized := img
+    if density != info.Density {
+      targetWidth, _ := scaleDimension(width, info.Density, density)
+      targetHeight, _ := scaleDimension(height, info.Density, density)
+      resized = resize.Resize(uint(targetWidth), uint(targetHeight), img, resizeFilter())
+    }
+    if err := writeOutputImage(info.Asset, path, resized, info.Format); err != nil {
+      return err
+    }
+  }
+  addTargetAsset(target.Path(out, filename, MDPI))
+  return nil
+}
+
+// lockedTargetAssets adds the 1x images andy.lock says were written for
+// source, when it's unchanged and nothing is written. Only outputs named like
+// the source count, foo@2x.png is an output of foo.png too.
+func lockedTargetAssets(target outputTarget, source string) {
+  lockMutex.Lock()
+  asset := lockedAssets[projectPath(source)]
+  lockMutex.Unlock()
+  name := filepath.Base(source)
+  name = strings.TrimSuffix(name, filepath.Ext(name))
+  for output := range asset.Outputs {
+    file := filepath.Base(filepath.FromSlash(output))
+    if strings.TrimSuffix(file, filepath.Ext(file)) != name {
+      continue
+    }
+    if main := target.Path(targetOut(target), file, MDPI); projectPath(main) == output {
+      addTargetAsset(main)
+    }
+  }
+}
+
+// patchPubspec adds assets to the flutter assets section of the pubspec at
+// path, unless they or their folder are listed already. It edits the lines
+// rather than re-encoding the yaml so comments and formatting survive.
+func patchPubspec(path string, assets []string) error {
+  data, err := os.ReadFile(path)
+  if err != nil {
+    return err
+  }
+  lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+  indent := func(line string) int { return len(line) - len(strings.TrimLeft(line, " ")) }
+  skipped := func(line string) bool {
+    trimmed := strings.TrimSpace(line)
+    return trimmed == "" || strings.HasPrefix(trimmed, "#")
+  }
+  flutter, end := -1, len(lines)
+  for i, line := range lines {
+    if skipped(line) || indent(line) > 0 {
+      continue
+    }
+    if flutter >= 0 {
+      end = i
+      break
+    }
+    if strings.HasPrefix(line, "flutter:") {
+      flutter = i
+    }
+  }
+  section, last := -1, -1
+  itemIndent := "    "
+  for i := flutter + 1; flutter >= 0 && i < end; i++ {
+    if section < 0 {
+      if strings.HasPrefix(strings.TrimSpace(lines[i]), "assets:") {
+        section, last = i, i
+      }
+      continue
+    }
+    if skipped(lines[i]) {
+      continue
+    }
+    if indent(lines[i]) <= indent(lines[section]) || !strings.HasPrefix(strings.TrimSpace(lines[i]), "- ") {
+      break
+    }
+    itemIndent, last = lines[i][:indent(lines[i])], i
+  }
+
+  listed := map[string]bool{}
+  if section >= 0 {
+    for _, line := range lines[section + 1:last + 1] {
+      listed[strings.Trim(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "- ")), `"'`)] = true
+    }
+  }
+  var added []string
+  for _, asset := range assets {
+    asset = filepath.ToSlash(asset)
+    if rel, err := filepath.Rel(tryGetAbsPath(filepath.Dir(path)), tryGetAbsPath(asset)); err == nil {
+      asset = filepath.ToSlash(rel)
+    }
+    if listed[asset] || listed[strings.TrimSuffix(asset, filepath.Base(asset))] {
+      continue
+    }
+    listed[asset] = true
+    added = append(added, itemIndent + "- " + asset)
+  }
+  if len(added) == 0 {
+    return nil
+  }
+  switch {
+  case section >= 0:
+    lines = append(lines[:last + 1], append(added, lines[last + 1:]...)...)
+  case flutter >= 0:
+    added = append([]string{"  assets:"}, added...)
+    lines = append(lines[:flutter + 1], append(added, lines[flutter + 1:]...)...)
+  default:
+    lines = append(lines, append([]string{"", "flutter:", "  assets:"}, added...)...)
+  }
+  return replaceFile(path, []byte(strings.Join(lines, "\n") + "\n"))
+}
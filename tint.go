@@ -0,0 +1,64 @@
+package main
+
+import (
+  "log"
+  "path/filepath"
+  "strings"
+  "github.com/spf13/cobra"
+)
+
+var (
+  tintColor string
+  tintSuffix string
+  tintPreserveFormat bool
+  tintSourceSets []string
+)
+
+var tintCmd = &cobra.Command{
+  Use: "tint [assets]",
+  Short: "Recolor monochrome assets into a new drawable and generate all its densities.",
+  Run: func(cmd *cobra.Command, args []string) {
+    if len(args) < 1 {
+      log.Fatal("need one or more filenames.")
+    }
+    if tintColor == "" || tintSuffix == "" {
+      log.Fatal("need --color and --suffix.")
+    }
+    if err := checkOutputOptions(); err != nil {
+      log.Fatal(err)
+    }
+    if !cmd.Flags().Changed("source-set") {
+      tintSourceSets = config.SourceSets
+    }
+    c, err := parseHexColor(tintColor)
+    if err != nil { log.Fatal(err) }
+
+    for _, arg := range args {
+      drawableInfo, img, err := openDrawable(arg)
+      if err != nil { log.Fatal(err) }
+      if !tintPreserveFormat {
+        drawableInfo.Format = "png"
+      }
+      ext := filepath.Ext(drawableInfo.Filename)
+      drawableInfo.Filename = strings.TrimSuffix(drawableInfo.Filename, ext) + tintSuffix + ext
+
+      tinted := tint(img, c)
+      for _, resFolder := range targetResFolders(drawableInfo.ResFolder, tintSourceSets) {
+        target := drawableInfo
+        target.ResFolder = resFolder
+        if err := variantToFolders(&target, &tinted, ""); err != nil {
+          log.Fatal(err)
+        }
+      }
+    }
+  },
+}
+
+func init() {
+  addOutputFlags(tintCmd)
+  addMasterFlags(tintCmd)
+  tintCmd.Flags().StringVar(&tintColor, "color", "", "color to recolor the asset with, #RRGGBB or #AARRGGBB")
+  tintCmd.Flags().StringVar(&tintSuffix, "suffix", "", "suffix for the new drawable's name, e.g. _disabled")
+  tintCmd.Flags().BoolVar(&tintPreserveFormat, "preserve-format", false, "write generated assets in the source's format (jpeg, webp) instead of png")
+  tintCmd.Flags().StringSliceVarP(&tintSourceSets, "source-set", "s", nil, "source sets to write generated assets into (e.g. main,paid or all)")
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+  "fmt"
+  "image"
+  "image/color"
+  "image/draw"
+  "math"
+  "strconv"
+  "strings"
+  "github.com/nfnt/resize"
+)
+
+func toNRGBA(img image.Image) *image.NRGBA {
+  if nrgba, ok := img.(*image.NRGBA); ok && nrgba.Rect.Min == (image.Point{}) {
+    return nrgba
+  }
+  bounds := img.Bounds()
+  nrgba := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+  draw.Draw(nrgba, nrgba.Bounds(), img, bounds.Min, draw.Src)
+  return nrgba
+}
+
+func flipHorizontal(img image.Image) image.Image {
+  src := toNRGBA(img)
+  width, height := src.Rect.Dx(), src.Rect.Dy()
+  flipped := image.NewNRGBA(src.Rect)
+  for y := 0; y < height; y++ {
+    for x := 0; x < width; x++ {
+      flipped.SetNRGBA(width-1-x, y, src.NRGBAAt(x, y))
+    }
+  }
+  return flipped
+}
+
+func flipVertical(img image.Image) image.Image {
+  src := toNRGBA(img)
+  width, height := src.Rect.Dx(), src.Rect.Dy()
+  flipped := image.NewNRGBA(src.Rect)
+  for y := 0; y < height; y++ {
+    for x := 0; x < width; x++ {
+      flipped.SetNRGBA(x, height-1-y, src.NRGBAAt(x, y))
+    }
+  }
+  return flipped
+}
+
+func rotateClockwise(img image.Image) image.Image {
+  src := toNRGBA(img)
+  width, height := src.Rect.Dx(), src.Rect.Dy()
+  rotated := image.NewNRGBA(image.Rect(0, 0, height, width))
+  for y := 0; y < height; y++ {
+    for x := 0; x < width; x++ {
+      rotated.SetNRGBA(height-1-y, x, src.NRGBAAt(x, y))
+    }
+  }
+  return rotated
+}
+
+type transform func(image.Image) image.Image
+
+// parseHexColor accepts #RGB, #RRGGBB and Android-style #AARRGGBB colors.
+func parseHexColor(s string) (c color.NRGBA, err error) {
+  hex := strings.TrimPrefix(s, "#")
+  if len(hex) == 3 {
+    hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+  }
+  if len(hex) == 6 {
+    hex = "ff" + hex
+  }
+  value, parseErr := strconv.ParseUint(hex, 16, 32)
+  if len(hex) != 8 || parseErr != nil {
+    return c, fmt.Errorf("invalid color %q", s)
+  }
+  return color.NRGBA{A: uint8(value >> 24), R: uint8(value >> 16), G: uint8(value >> 8), B: uint8(value)}, nil
+}
+
+func mapPixels(img image.Image, fn func(color.NRGBA) color.NRGBA) image.Image {
+  src := toNRGBA(img)
+  out := image.NewNRGBA(src.Rect)
+  for y := src.Rect.Min.Y; y < src.Rect.Max.Y; y++ {
+    for x := src.Rect.Min.X; x < src.Rect.Max.X; x++ {
+      out.SetNRGBA(x, y, fn(src.NRGBAAt(x, y)))
+    }
+  }
+  return out
+}
+
+func invert(img image.Image) image.Image {
+  return mapPixels(img, func(c color.NRGBA) color.NRGBA {
+    return color.NRGBA{255 - c.R, 255 - c.G, 255 - c.B, c.A}
+  })
+}
+
+// tint replaces the color of every pixel with c, keeping the pixel's alpha
+// scaled by the alpha of c.
+func tint(img image.Image, c color.NRGBA) image.Image {
+  return mapPixels(img, func(p color.NRGBA) color.NRGBA {
+    return color.NRGBA{c.R, c.G, c.B, uint8(uint32(p.A) * uint32(c.A) / 255)}
+  })
+}
+
+// opacity scales the alpha of every pixel by factor.
+func opacity(img image.Image, factor float64) image.Image {
+  return mapPixels(img, func(c color.NRGBA) color.NRGBA {
+    return color.NRGBA{c.R, c.G, c.B, uint8(math.Min(255, math.Round(float64(c.A)*factor)))}
+  })
+}
+
+func brightness(img image.Image, factor float64) image.Image {
+  scale := func(v uint8) uint8 {
+    return uint8(math.Min(255, math.Round(float64(v)*factor)))
+  }
+  return mapPixels(img, func(c color.NRGBA) color.NRGBA {
+    return color.NRGBA{scale(c.R), scale(c.G), scale(c.B), c.A}
+  })
+}
+
+// compose stacks layers bottom to top, each scaled to fill a size x size canvas.
+func compose(size int, layers ...image.Image) *image.NRGBA {
+  canvas := image.NewNRGBA(image.Rect(0, 0, size, size))
+  for _, layer := range layers {
+    scaled := resize.Resize(uint(size), uint(size), layer, resize.Lanczos3)
+    draw.Draw(canvas, canvas.Bounds(), scaled, scaled.Bounds().Min, draw.Over)
+  }
+  return canvas
+}
+
+func solidImage(width int, height int, c color.Color) *image.NRGBA {
+  img := image.NewNRGBA(image.Rect(0, 0, width, height))
+  draw.Draw(img, img.Bounds(), &image.Uniform{c}, image.Point{}, draw.Src)
+  return img
+}
+
+// cropCenter returns the centered width x height region of img.
+func cropCenter(img image.Image, width int, height int) *image.NRGBA {
+  bounds := img.Bounds()
+  cropped := image.NewNRGBA(image.Rect(0, 0, width, height))
+  offset := image.Pt(bounds.Min.X + (bounds.Dx()-width)/2, bounds.Min.Y + (bounds.Dy()-height)/2)
+  draw.Draw(cropped, cropped.Bounds(), img, offset, draw.Src)
+  return cropped
+}
+
+// threshold turns img into a white silhouette: pixels whose alpha is above
+// cutoff (0-1) become opaque, everything else transparent.
+func threshold(img image.Image, cutoff float64) image.Image {
+  return mapPixels(img, func(c color.NRGBA) color.NRGBA {
+    if float64(c.A) / 255 > cutoff {
+      return color.NRGBA{255, 255, 255, 255}
+    }
+    return color.NRGBA{}
+  })
+}
+
+// circleMask scales img by scale around its center and clips it to the
+// largest circle that fits, with an antialiased edge.
+func circleMask(img image.Image, scale float64) *image.NRGBA {
+  width, height := getDimens(&img)
+  size := width
+  if height < size {
+    size = height
+  }
+  scaledSize := int(math.Round(float64(size) * scale))
+  scaled := toNRGBA(resize.Resize(uint(scaledSize), uint(scaledSize), img, resize.Lanczos3))
+
+  masked := image.NewNRGBA(image.Rect(0, 0, size, size))
+  offset := image.Pt((size-scaledSize)/2, (size-scaledSize)/2)
+  draw.Draw(masked, masked.Bounds(), scaled, offset.Mul(-1), draw.Src)
+
+  radius := float64(size) / 2
+  for y := 0; y < size; y++ {
+    for x := 0; x < size; x++ {
+      distance := math.Hypot(float64(x)+0.5-radius, float64(y)+0.5-radius)
+      coverage := math.Max(0, math.Min(1, radius-distance+0.5))
+      c := masked.NRGBAAt(x, y)
+      c.A = uint8(math.Round(float64(c.A) * coverage))
+      masked.SetNRGBA(x, y, c)
+    }
+  }
+  return masked
+}
+
+// silhouette turns img into white on transparent, taking the alpha from the
+// image's own alpha, its luminance, or its darkness (for dark-on-light art).
+func silhouette(img image.Image, alphaFrom string) (image.Image, error) {
+  var alpha func(color.NRGBA) float64
+  luminance := func(c color.NRGBA) float64 {
+    return (0.2126*float64(c.R) + 0.7152*float64(c.G) + 0.0722*float64(c.B)) / 255
+  }
+  switch alphaFrom {
+  case "alpha":
+    alpha = func(c color.NRGBA) float64 { return 1 }
+  case "luminance":
+    alpha = luminance
+  case "darkness":
+    alpha = func(c color.NRGBA) float64 { return 1 - luminance(c) }
+  default:
+    return nil, fmt.Errorf("unknown alpha source %q", alphaFrom)
+  }
+  return mapPixels(img, func(c color.NRGBA) color.NRGBA {
+    return color.NRGBA{255, 255, 255, uint8(math.Round(float64(c.A) * alpha(c)))}
+  }), nil
+}
+
+// fitCentered scales img to fit a contentDp square and centers it on a
+// transparent canvasDp square, rendered at pxPerDp.
+func fitCentered(img image.Image, canvasDp float64, contentDp float64, pxPerDp float64) *image.NRGBA {
+  width, height := getDimens(&img)
+  scale := contentDp * pxPerDp / math.Max(float64(width), float64(height))
+  scaledWidth, scaledHeight := int(math.Round(float64(width)*scale)), int(math.Round(float64(height)*scale))
+  scaled := resize.Resize(uint(scaledWidth), uint(scaledHeight), img, resize.Lanczos3)
+
+  size := int(math.Round(canvasDp * pxPerDp))
+  canvas := image.NewNRGBA(image.Rect(0, 0, size, size))
+  offset := image.Pt((size-scaledWidth)/2, (size-scaledHeight)/2)
+  draw.Draw(canvas, scaled.Bounds().Add(offset), scaled, scaled.Bounds().Min, draw.Over)
+  return canvas
+}
+
+// cropToAspect center-crops img to the aspect ratio of width:height,
+// reporting whether anything had to be cut.
+func cropToAspect(img image.Image, width float64, height float64) (image.Image, bool) {
+  imgWidth, imgHeight := getDimens(&img)
+  targetWidth, targetHeight := imgWidth, int(math.Round(float64(imgWidth)*height/width))
+  if targetHeight > imgHeight {
+    targetWidth, targetHeight = int(math.Round(float64(imgHeight)*width/height)), imgHeight
+  }
+  if targetWidth == imgWidth && targetHeight == imgHeight {
+    return img, false
+  }
+  return cropCenter(img, targetWidth, targetHeight), true
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+  "fmt"
+  "log"
+  "os"
+  "path/filepath"
+  "sort"
+  "strings"
+  "github.com/BurntSushi/toml"
+  "github.com/spf13/cobra"
+)
+
+var undoCmd = &cobra.Command{
+  Use: "undo",
+  Short: "Reverse the most recent run that wrote files.",
+  Long: `Reverse the most recent run that wrote files.
+
+Files the run created are deleted and files it replaced or removed are
+restored from their backups. Replaced files can only be restored if the run
+was made with --backup, removed files and andy.lock always are. Run it again
+to undo the run before.`,
+  Run: func(cmd *cobra.Command, args []string) {
+    path, err := lastJournal()
+    if err != nil { log.Fatal(err) }
+    var journal Journal
+    if _, err := toml.DecodeFile(path, &journal); err != nil {
+      log.Fatalf("%s: %v", path, err)
+    }
+
+    fmt.Printf("%s %s\n", green("undo"), strings.TrimSuffix(filepath.Base(path), ".toml"))
+    lost := 0
+    for i := len(journal.Files) - 1; i >= 0; i-- {
+      entry := journal.Files[i]
+      target := filepath.FromSlash(entry.Path)
+      switch {
+      case entry.Created:
+        if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+          log.Fatal(err)
+        }
+        // drop folders the run created, if nothing else is in them
+        os.Remove(filepath.Dir(target))
+        fmt.Printf("  %s %s\n", yellow("removed"), target)
+      case entry.Backup != "":
+        data, err := os.ReadFile(filepath.FromSlash(entry.Backup))
+        if err != nil { log.Fatal(err) }
+        if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil { log.Fatal(err) }
+        if err := writeAtomic(target, data); err != nil { log.Fatal(err) }
+        fmt.Printf("  %s %s\n", green("restored"), target)
+      default:
+        fmt.Printf("  %s %s was overwritten without --backup, can't restore it\n", yellow("warn"), target)
+        lost++
+      }
+    }
+    if err := os.Remove(path); err != nil { log.Fatal(err) }
+    if lost > 0 {
+      fmt.Printf("%s %d files couldn't be restored\n", yellow("undo"), lost)
+    }
+  },
+}
+
+// lastJournal is the journal of the most recent run. Journals are named by
+// when their run started, so they sort in order.
+func lastJournal() (string, error) {
+  entries, err := os.ReadDir(journalDir)
+  if err != nil && !os.IsNotExist(err) {
+    return "", err
+  }
+  var names []string
+  for _, entry := range entries {
+    if !entry.IsDir() && filepath.Ext(entry.Name()) == ".toml" {
+      names = append(names, entry.Name())
+    }
+  }
+  if len(names) == 0 {
+    return "", fmt.Errorf("nothing to undo, no runs in %s", journalDir)
+  }
+  sort.Strings(names)
+  return filepath.Join(journalDir, names[len(names) - 1]), nil
+}
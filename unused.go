@@ -0,0 +1,242 @@
+package main
+
+import (
+  "fmt"
+  "io/fs"
+  "log"
+  "os"
+  "path/filepath"
+  "regexp"
+  "sort"
+  "strings"
+  "github.com/spf13/cobra"
+)
+
+var (
+  unusedDelete bool
+  unusedInteractive bool
+  unusedForce bool
+)
+
+var unusedCmd = &cobra.Command{
+  Use: "unused [res folders]",
+  Short: "List drawables nothing in the project references.",
+  Long: `List drawables nothing in the project references.
+
+Every xml file (layouts, menus, manifests, other drawables) and Kotlin or
+Java source under the working directory is searched for @drawable/name,
+@mipmap/name and R.drawable.name references. Drawables that are never
+referenced are listed with the bytes they take across all their densities.
+Resources only looked up by name at runtime will show up too, check before
+deleting them.
+
+--delete removes the unused drawables from every density and qualifier
+folder, after listing the files and asking. --interactive asks about each
+drawable instead. Deleted files can be brought back with andy undo.
+
+With --baseline, drawables already unused when the baseline was written are
+left out, and the command exits nonzero if any others are unused, so CI only
+fails on new ones. --output json prints the unused drawables with their
+files for scripts, and sarif or github annotates them on pull requests.
+
+  andy unused
+  andy unused --delete
+  andy unused --baseline andy-baseline.json`,
+  Run: func(cmd *cobra.Command, args []string) {
+    if len(args) > 0 {
+      resDirs = args
+    }
+    if err := checkReportFormat(cmd); err != nil { log.Fatal(err) }
+    if reportFormat != "table" && (unusedDelete || unusedInteractive) {
+      log.Fatal("--delete and --interactive only work with --output table.")
+    }
+    resFolders, err := guessResFolders()
+    if err != nil { log.Fatal(err) }
+    files, err := scanResFiles(resFolders)
+    if err != nil { log.Fatal(err) }
+    refs, err := findReferences(".")
+    if err != nil { log.Fatal(err) }
+
+    unused, names := unusedDrawables(files, refs)
+    if baselinePath != "" || updateBaseline {
+      var findings []Finding
+      for _, name := range names {
+        findings = append(findings, Finding{Check: "unused", Path: name, Message: "isn't referenced"})
+      }
+      findings, err = applyBaseline(findings)
+      if err != nil { log.Fatal(err) }
+      names = nil
+      for _, finding := range findings {
+        names = append(names, finding.Path)
+      }
+    }
+    switch reportFormat {
+    case "json":
+      if err := printUnusedJSON(unused, names); err != nil { log.Fatal(err) }
+    case "sarif", "github":
+      // annotations need a file, so they point at the highest density
+      var findings []Finding
+      for _, name := range names {
+        files := unused[name]
+        sort.Slice(files, func(i, j int) bool { return files[i].Density > files[j].Density })
+        findings = append(findings, Finding{Check: "unused", Path: files[0].Path, Message: fmt.Sprintf("isn't referenced as %s anywhere", name)})
+      }
+      if err := printFindingsReport(len(names), findings); err != nil { log.Fatal(err) }
+    }
+    if reportFormat != "table" {
+      if baselinePath != "" && len(names) > 0 {
+        os.Exit(1)
+      }
+      return
+    }
+    var total int64
+    count := 0
+    for _, name := range names {
+      var size int64
+      for _, file := range unused[name] {
+        size += file.Size
+      }
+      total += size
+      count += len(unused[name])
+      fmt.Printf("  %s %s (%d files, %s)\n", yellow("unused"), name, len(unused[name]), formatBytes(size))
+    }
+    if len(names) == 0 {
+      if baselinePath != "" {
+        fmt.Printf("%s no drawables unused since the baseline\n", green("unused"))
+      } else {
+        fmt.Printf("%s every drawable is referenced\n", green("unused"))
+      }
+      return
+    }
+    fmt.Printf("%s %d drawables in %d files, %s\n", yellow("unused"), len(names), count, formatBytes(total))
+    if !unusedDelete && !unusedInteractive {
+      if baselinePath != "" {
+        os.Exit(1)
+      }
+      return
+    }
+
+    var remove []resFile
+    if unusedInteractive {
+      for _, name := range names {
+        if confirm(fmt.Sprintf("delete %s (%s)?", name, filePaths(unused[name]))) {
+          remove = append(remove, unused[name]...)
+        }
+      }
+    } else {
+      for _, name := range names {
+        for _, file := range unused[name] {
+          fmt.Printf("  %s\n", file.Path)
+          remove = append(remove, file)
+        }
+      }
+      if !unusedForce && !confirm(fmt.Sprintf("delete these %d files?", len(remove))) {
+        log.Fatal("nothing deleted, pass --force to delete without asking.")
+      }
+    }
+    for _, file := range remove {
+      if err := removeFile(file.Path); err != nil { log.Fatal(err) }
+    }
+  },
+}
+
+func init() {
+  unusedCmd.Flags().BoolVar(&unusedDelete, "delete", false, "delete the unused drawables from every folder, after asking")
+  unusedCmd.Flags().BoolVar(&unusedInteractive, "interactive", false, "ask about deleting each unused drawable")
+  unusedCmd.Flags().BoolVar(&unusedForce, "force", false, "delete without asking")
+  addBaselineFlags(unusedCmd)
+  addReportFlags(unusedCmd, "table", "json", "sarif", "github")
+}
+
+type unusedJSON struct {
+  Name string `json:"name"`
+  Bytes int64 `json:"bytes"`
+  Files []fileJSON `json:"files"`
+}
+
+func printUnusedJSON(unused map[string][]resFile, names []string) error {
+  report := []unusedJSON{}
+  for _, name := range names {
+    drawable := unusedJSON{Name: name}
+    for _, file := range unused[name] {
+      drawable.Bytes += file.Size
+      drawable.Files = append(drawable.Files, file.JSON())
+    }
+    report = append(report, drawable)
+  }
+  return printJSON(report)
+}
+
+func filePaths(files []resFile) string {
+  var paths []string
+  for _, file := range files {
+    paths = append(paths, file.Path)
+  }
+  return strings.Join(paths, ", ")
+}
+
+// resourceKey is how code refers to a file, e.g. drawable/ic_logo.
+func (file resFile) resourceKey() string {
+  return file.Type + "/" + file.Name
+}
+
+// unusedDrawables groups the files of each drawable that isn't in refs by
+// its resource key, in order.
+func unusedDrawables(files []resFile, refs map[string]bool) (map[string][]resFile, []string) {
+  unused := map[string][]resFile{}
+  for _, file := range files {
+    if !refs[file.resourceKey()] {
+      unused[file.resourceKey()] = append(unused[file.resourceKey()], file)
+    }
+  }
+  var names []string
+  for name := range unused {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+  return unused, names
+}
+
+var referenceRegex = regexp.MustCompile(`(?:@|\bR\.)(drawable|mipmap)[/.]([A-Za-z0-9_]+)`)
+
+// skippedDirs never have sources worth searching.
+var skippedDirs = map[string]bool{".git": true, ".gradle": true, ".idea": true, ".andy": true, "build": true, "node_modules": true}
+
+// findReferences collects the drawable and mipmap resources referenced from
+// the xml, Kotlin and Java files under root, as resource keys.
+func findReferences(root string) (map[string]bool, error) {
+  refs := map[string]bool{}
+  err := walkSources(root, func(path string, data []byte) error {
+    for _, match := range referenceRegex.FindAllSubmatch(data, -1) {
+      refs[string(match[1]) + "/" + string(match[2])] = true
+    }
+    return nil
+  })
+  return refs, err
+}
+
+// walkSources calls fn with every xml, Kotlin and Java file under root that
+// could reference resources.
+func walkSources(root string, fn func(path string, data []byte) error) error {
+  return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+    if err != nil {
+      return err
+    }
+    if entry.IsDir() {
+      if path != root && (skippedDirs[entry.Name()] || ignored(path, true)) {
+        return filepath.SkipDir
+      }
+      return nil
+    }
+    switch filepath.Ext(path) {
+    case ".xml", ".kt", ".java":
+    default:
+      return nil
+    }
+    data, err := os.ReadFile(path)
+    if err != nil {
+      return err
+    }
+    return fn(path, data)
+  })
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+  "fmt"
+  "image"
+  "math"
+  "path/filepath"
+  "sort"
+)
+
+// checkUpscaled flags density variants that look upscaled from a smaller
+// image rather than exported at their own size. Exported variants keep edges
+// about as sharp as the density below; upscaled ones have edges softer by
+// about the scale factor.
+func checkUpscaled(resFolders []string) (findings []Finding, err error) {
+  for _, resFolder := range resFolders {
+    files, err := scanResFiles([]string{resFolder})
+    if err != nil {
+      return nil, err
+    }
+    groups, names := groupResources(files)
+    for _, name := range names {
+      group := groups[name]
+      for i := 1; i < len(group); i++ {
+        lower, file := group[i - 1], group[i]
+        if isNinePatch(filepath.Base(file.Path)) {
+          continue
+        }
+        lowerEdges, err := edgeSharpness(lower.Path)
+        if err != nil {
+          return nil, err
+        }
+        edges, err := edgeSharpness(file.Path)
+        if err != nil {
+          return nil, err
+        }
+        scale := float64(file.Density) / float64(lower.Density)
+        if lowerEdges == 0 || edges == 0 {
+          continue
+        }
+        // halfway between exported (1) and upscaled (1/scale)
+        if edges / lowerEdges < (1 + 1 / scale) / 2 {
+          findings = append(findings, Finding{Check: "upscaled", Path: file.Path, Message: fmt.Sprintf("looks upscaled from %s, its edges are %.1fx softer", densityToCanonical[lower.Density], lowerEdges / edges)})
+        }
+      }
+    }
+  }
+  return
+}
+
+// edgeSharpness is the strength of the sharpest edges in the image at path:
+// the 99th percentile of the gradient of its luminance and alpha, or 0 for
+// images too small or flat to tell.
+func edgeSharpness(path string) (float64, error) {
+  img, _, err := decodeImageFile(path)
+  if err != nil {
+    return 0, err
+  }
+  bounds := img.Bounds()
+  if bounds.Dx() < 16 || bounds.Dy() < 16 {
+    return 0, nil
+  }
+  luma := make([]float64, bounds.Dx() * bounds.Dy())
+  for y := 0; y < bounds.Dy(); y++ {
+    for x := 0; x < bounds.Dx(); x++ {
+      luma[y * bounds.Dx() + x] = lumaAlpha(img, bounds.Min.X + x, bounds.Min.Y + y)
+    }
+  }
+  var gradients []float64
+  for y := 1; y < bounds.Dy() - 1; y++ {
+    for x := 1; x < bounds.Dx() - 1; x++ {
+      at := func(dx, dy int) float64 { return luma[(y + dy) * bounds.Dx() + x + dx] }
+      gx := at(1, 0) - at(-1, 0)
+      gy := at(0, 1) - at(0, -1)
+      if g := math.Hypot(gx, gy); g > 0.01 {
+        gradients = append(gradients, g)
+      }
+    }
+  }
+  if len(gradients) < 8 {
+    return 0, nil
+  }
+  sort.Float64s(gradients)
+  return gradients[len(gradients) * 99 / 100], nil
+}
+
+func lumaAlpha(img image.Image, x int, y int) float64 {
+  r, g, b, a := img.At(x, y).RGBA()
+  // RGBA is already premultiplied, so transparent pixels count as black
+  return (0.299 * float64(r) + 0.587 * float64(g) + 0.114 * float64(b) + float64(a)) / 2 / 0xffff
+}
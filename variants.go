@@ -0,0 +1,58 @@
+package main
+
+import (
+  "fmt"
+  "math"
+  "path/filepath"
+)
+
+// checkVariants compares every density of each drawable with its highest
+// one, and flags variants whose aspect ratio differs or whose size doesn't
+// match their density, the usual signs of a stale export.
+func checkVariants(resFolders []string) (findings []Finding, err error) {
+  for _, resFolder := range resFolders {
+    files, err := scanResFiles([]string{resFolder})
+    if err != nil {
+      return nil, err
+    }
+    groups, names := groupResources(files)
+    for _, name := range names {
+      group := groups[name]
+      reference := group[len(group) - 1]
+      refWidth, refHeight, err := contentSize(reference)
+      if err != nil {
+        return nil, err
+      }
+      for _, file := range group[:len(group) - 1] {
+        width, height, err := contentSize(file)
+        if err != nil {
+          return nil, err
+        }
+        scale := float64(file.Density) / float64(reference.Density)
+        expectedWidth, expectedHeight := refWidth * scale, refHeight * scale
+        ref := fmt.Sprintf("%s's %gx%g", densityToCanonical[reference.Density], refWidth, refHeight)
+        switch {
+        case math.Abs(width * refHeight - height * refWidth) > refWidth + refHeight:
+          // more than a pixel of rounding on either side can explain
+          findings = append(findings, Finding{Check: "variants", Path: file.Path, Message: fmt.Sprintf("is %gx%g, a different aspect ratio than %s", width, height, ref)})
+        case math.Abs(width - expectedWidth) > 1.5 || math.Abs(height - expectedHeight) > 1.5:
+          findings = append(findings, Finding{Check: "variants", Path: file.Path, Message: fmt.Sprintf("is %gx%g, expected %sx%s from %s", width, height, formatSize(expectedWidth), formatSize(expectedHeight), ref)})
+        }
+      }
+    }
+  }
+  return
+}
+
+// contentSize is the size of file in pixels, without a nine-patch's border.
+func contentSize(file resFile) (width float64, height float64, err error) {
+  dimens, err := file.Dimens()
+  if err != nil {
+    return 0, 0, fmt.Errorf("%s: %v", file.Path, err)
+  }
+  border := 0
+  if isNinePatch(filepath.Base(file.Path)) {
+    border = 2
+  }
+  return float64(dimens.X - border), float64(dimens.Y - border), nil
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+  "fmt"
+  "image"
+  "log"
+  "path/filepath"
+  "sort"
+  "github.com/spf13/cobra"
+)
+
+var (
+  vectorsMaxColors int
+  vectorsMaxDp float64
+)
+
+var vectorsCmd = &cobra.Command{
+  Use: "vectors [res folders]",
+  Short: "Suggest bitmap drawables that would be smaller as a VectorDrawable.",
+  Long: `Suggest bitmap drawables that would be smaller as a VectorDrawable.
+
+The highest density of every drawable is looked at: small images (up to
+--max-dp) drawn with few flat colors (up to --max-colors, not counting the
+blend at their edges) are usually icons, and one VectorDrawable could replace
+all their densities. Each candidate is listed with what its bitmaps take and
+a rough estimate of the vector's size, from the length of its outlines.
+
+  andy vectors
+  andy vectors --max-colors 2`,
+  Run: func(cmd *cobra.Command, args []string) {
+    if len(args) > 0 {
+      resDirs = args
+    }
+    resFolders, err := guessResFolders()
+    if err != nil { log.Fatal(err) }
+    files, err := scanResFiles(resFolders)
+    if err != nil { log.Fatal(err) }
+
+    groups, names := groupResources(files)
+    var saved int64
+    candidates := 0
+    for _, name := range names {
+      group := groups[name]
+      source := group[len(group) - 1]
+      if isNinePatch(filepath.Base(source.Path)) {
+        continue
+      }
+      img, _, err := decodeImageFile(source.Path)
+      if err != nil { log.Fatal(err) }
+      bounds := img.Bounds()
+      scale := float64(source.Density) / MDPI
+      if float64(bounds.Dx()) / scale > vectorsMaxDp || float64(bounds.Dy()) / scale > vectorsMaxDp {
+        continue
+      }
+      colors := flatColors(img)
+      if colors == 0 || colors > vectorsMaxColors {
+        continue
+      }
+      var size int64
+      for _, file := range group {
+        size += file.Size
+      }
+      estimate := vectorEstimate(img, scale)
+      if estimate >= size {
+        continue
+      }
+      candidates++
+      saved += size - estimate
+      fmt.Printf("  %s %s (%d files, %s, about %s as a vector, %d colors)\n", green("vector"), name, len(group), formatBytes(size), formatBytes(estimate), colors)
+    }
+    if candidates == 0 {
+      fmt.Printf("%s no candidates in %d drawables\n", green("vectors"), len(names))
+      return
+    }
+    fmt.Printf("%s %d candidates, about %s smaller as vectors\n", green("vectors"), candidates, formatBytes(saved))
+  },
+}
+
+func init() {
+  vectorsCmd.Flags().IntVar(&vectorsMaxColors, "max-colors", 3, "most flat colors a candidate can have")
+  vectorsCmd.Flags().Float64Var(&vectorsMaxDp, "max-dp", 96, "largest width or height in dp a candidate can have")
+}
+
+// flatColors is how many colors cover nearly all of the visible pixels of
+// img, or 0 if it takes more than a handful, like a photo or gradient.
+// Colors are compared at 4 bits a channel, so dithering and compression
+// noise don't count as colors of their own.
+func flatColors(img image.Image) int {
+  bounds := img.Bounds()
+  counts := map[[3]uint32]int{}
+  visible := 0
+  for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+    for x := bounds.Min.X; x < bounds.Max.X; x++ {
+      r, g, b, a := img.At(x, y).RGBA()
+      if a < 0xf000 {
+        // transparent, or antialiased edges
+        if a > 0 {
+          visible++
+        }
+        continue
+      }
+      visible++
+      counts[[3]uint32{r >> 12, g >> 12, b >> 12}]++
+    }
+  }
+  if visible == 0 {
+    return 0
+  }
+  var sizes []int
+  for _, count := range counts {
+    sizes = append(sizes, count)
+  }
+  sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
+  covered := 0
+  for i, count := range sizes {
+    covered += count
+    // blends between colors are scattered over many little ones
+    if covered * 100 >= visible * 90 {
+      return i + 1
+    }
+    if i >= 16 {
+      break
+    }
+  }
+  return 0
+}
+
+// vectorEstimate guesses the bytes of a VectorDrawable tracing img: the xml
+// around it plus every outline pixel at mdpi as a few bytes of path data.
+func vectorEstimate(img image.Image, scale float64) int64 {
+  bounds := img.Bounds()
+  outline := 0
+  for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+    for x := bounds.Min.X + 1; x < bounds.Max.X; x++ {
+      if (lumaAlpha(img, x, y) > 0.5) != (lumaAlpha(img, x - 1, y) > 0.5) {
+        outline++
+      }
+    }
+  }
+  return 300 + int64(float64(outline) / scale * 2 * 6)
+}
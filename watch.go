@@ -0,0 +1,155 @@
+package main
+
+import (
+  "fmt"
+  "log"
+  "os"
+  "path/filepath"
+  "time"
+  "github.com/spf13/cobra"
+)
+
+var (
+  watchMasters string
+  watchDensity string
+  watchInterval time.Duration
+  watchSourceSets []string
+)
+
+var watchCmd = &cobra.Command{
+  Use: "watch [res folders]",
+  Short: "Regenerate lower densities whenever a source image changes.",
+  Long: `Regenerate lower densities whenever a source image changes.
+
+The highest density of every drawable in the res folders is watched. With
+--masters, the images in that folder are watched instead and written into
+the res folder at the --at density and below.
+
+  andy watch
+  andy watch --masters design/export --at xxxhdpi`,
+  Run: func(cmd *cobra.Command, args []string) {
+    if err := checkOutputOptions(); err != nil {
+      log.Fatal(err)
+    }
+    if !cmd.Flags().Changed("source-set") {
+      watchSourceSets = config.SourceSets
+    }
+    // regenerating is the point, don't ask before each overwrite
+    outputOptions.Force = !outputOptions.SkipExisting
+    if len(args) > 0 {
+      resDirs = args
+    }
+    resFolders, err := guessResFolders()
+    if err != nil { log.Fatal(err) }
+
+    var masterDensity dpi
+    if watchMasters != "" {
+      if !dirExists(watchMasters) {
+        log.Fatalf("masters folder %s not found", watchMasters)
+      }
+      if masterDensity, err = parseDensity(watchDensity); err != nil { log.Fatal(err) }
+      if _, ok := densityToFolder[masterDensity]; !ok {
+        log.Fatalf("--at must be one of the density folders, not %s", watchDensity)
+      }
+    }
+
+    sources := func() map[string]dpi {
+      found := map[string]dpi{}
+      if watchMasters != "" {
+        masters, _ := folderImages(watchMasters)
+        for _, path := range masters {
+          found[path] = masterDensity
+        }
+        return found
+      }
+      for _, resFolder := range resFolders {
+        for path, density := range sourceDrawables(resFolder) {
+          found[path] = density
+        }
+      }
+      return found
+    }
+
+    seen := map[string]time.Time{}
+    for path := range sources() {
+      seen[path] = modTime(path)
+    }
+    where := watchMasters
+    if where == "" {
+      where = tryGetAbsPath(resFolders[0])
+    }
+    fmt.Printf("%s %d sources in %s, ctrl-c to stop\n", green("watching"), len(seen), where)
+
+    for range time.Tick(watchInterval) {
+      for path, density := range sources() {
+        changed := modTime(path)
+        if last, ok := seen[path]; ok && !changed.After(last) {
+          continue
+        }
+        var err error
+        if watchMasters != "" {
+          err = regenerateMaster(path, resFolders[0], density)
+        } else {
+          err = regenerate(path)
+        }
+        if err != nil {
+          // likely still being written, try again on the next tick
+          fmt.Printf("  %s %s: %v\n", yellow("warn"), path, err)
+          continue
+        }
+        seen[path] = changed
+      }
+    }
+  },
+}
+
+func init() {
+  addOutputFlags(watchCmd)
+  watchCmd.Flags().StringVar(&watchMasters, "masters", "", "folder of master images to watch instead of the res folders")
+  watchCmd.Flags().StringVar(&watchDensity, "at", "xxxhdpi", "density of the images in --masters")
+  watchCmd.Flags().DurationVar(&watchInterval, "interval", 500 * time.Millisecond, "how often to look for changes")
+  watchCmd.Flags().StringSliceVarP(&watchSourceSets, "source-set", "s", nil, "source sets to write generated assets into (e.g. main,paid or all)")
+}
+
+func modTime(path string) time.Time {
+  info, err := os.Stat(path)
+  if err != nil {
+    return time.Time{}
+  }
+  return info.ModTime()
+}
+
+// regenerate resizes a changed source in the res tree to lower densities.
+func regenerate(path string) error {
+  drawableInfo, img, err := openDrawable(path)
+  if err != nil {
+    return err
+  }
+  drawableInfo.Format = "png"
+  for _, resFolder := range targetResFolders(drawableInfo.ResFolder, watchSourceSets) {
+    target := drawableInfo
+    target.ResFolder = resFolder
+    if err := resizeToFolders(&target, &img); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+
+// regenerateMaster writes a changed master into resFolder at density and below.
+func regenerateMaster(path string, resFolder string, density dpi) error {
+  img, _, err := decodeImageFile(path)
+  if err != nil {
+    return err
+  }
+  fmt.Printf("%s %s\n", green("from"), path)
+  drawableInfo := DrawableInfo{ResFolder: tryGetAbsPath(resFolder), Density: density, Filename: withFormatExtension(filepath.Base(path), "png"), Format: "png"}
+  for _, target := range targetResFolders(drawableInfo.ResFolder, watchSourceSets) {
+    variant := drawableInfo
+    variant.ResFolder = target
+    if err := variantToFolders(&variant, &img, ""); err != nil {
+      return err
+    }
+  }
+  return nil
+}
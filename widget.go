@@ -0,0 +1,135 @@
+package main
+
+import (
+  "encoding/xml"
+  "errors"
+  "fmt"
+  "log"
+  "os"
+  "strconv"
+  "strings"
+  "github.com/spf13/cobra"
+)
+
+type AppWidgetProvider struct {
+  MinWidth string `xml:"http://schemas.android.com/apk/res/android minWidth,attr"`
+  MinHeight string `xml:"http://schemas.android.com/apk/res/android minHeight,attr"`
+  TargetCellWidth string `xml:"http://schemas.android.com/apk/res/android targetCellWidth,attr"`
+  TargetCellHeight string `xml:"http://schemas.android.com/apk/res/android targetCellHeight,attr"`
+  PreviewImage string `xml:"http://schemas.android.com/apk/res/android previewImage,attr"`
+}
+
+var (
+  widgetProvider string
+  widgetName string
+  widgetSize string
+  widgetSourceSets []string
+)
+
+var widgetPreviewCmd = &cobra.Command{
+  Use: "widget-preview [screenshot]",
+  Short: "Generate a widget's previewImage for every density at the size from its appwidget-provider XML.",
+  Run: func(cmd *cobra.Command, args []string) {
+    if len(args) != 1 {
+      log.Fatal("need one master screenshot.")
+    }
+    if widgetProvider == "" && (widgetName == "" || widgetSize == "") {
+      log.Fatal("need --provider, or both --name and --size.")
+    }
+    if err := checkOutputOptions(); err != nil {
+      log.Fatal(err)
+    }
+    if !cmd.Flags().Changed("source-set") {
+      widgetSourceSets = config.SourceSets
+    }
+
+    name, widthDp, heightDp := widgetName, 0.0, 0.0
+    if widgetProvider != "" {
+      provider, err := readAppWidgetProvider(widgetProvider)
+      if err != nil { log.Fatal(err) }
+      if name == "" {
+        name = strings.TrimPrefix(provider.PreviewImage, "@drawable/")
+      }
+      widthDp, heightDp, err = provider.sizeDp()
+      if err != nil { log.Fatal(fmt.Errorf("%s: %v", widgetProvider, err)) }
+    }
+    if widgetSize != "" {
+      var err error
+      widthDp, heightDp, err = parseDpSize(widgetSize)
+      if err != nil { log.Fatal(err) }
+    }
+    if name == "" {
+      log.Fatal("the provider has no android:previewImage, pass --name.")
+    }
+
+    screenshot, _, err := decodeImageFile(args[0])
+    if err != nil { log.Fatal(err) }
+    fmt.Printf("%s %s (%gx%gdp)\n", green("from"), args[0], widthDp, heightDp)
+    preview, cropped := cropToAspect(screenshot, widthDp, heightDp)
+    if cropped {
+      fmt.Printf("  %s screenshot cropped to the widget's %gx%gdp aspect ratio\n", yellow("warn"), widthDp, heightDp)
+    }
+
+    resFolder, err := defaultResFolder()
+    if err != nil { log.Fatal(err) }
+    for _, target := range targetResFolders(resFolder, widgetSourceSets) {
+      if err := writeDensitySet(target, "drawable", name + ".png", preview, widthDp, heightDp); err != nil {
+        log.Fatal(err)
+      }
+    }
+  },
+}
+
+func init() {
+  addOutputFlags(widgetPreviewCmd)
+  widgetPreviewCmd.Flags().StringVar(&widgetProvider, "provider", "", "appwidget-provider XML to take the size and previewImage name from")
+  widgetPreviewCmd.Flags().StringVar(&widgetName, "name", "", "resource name of the preview (default: the provider's previewImage)")
+  widgetPreviewCmd.Flags().StringVar(&widgetSize, "size", "", "preview size such as 250x110dp (default: from the provider)")
+  widgetPreviewCmd.Flags().StringSliceVarP(&widgetSourceSets, "source-set", "s", nil, "source sets to write generated assets into (e.g. main,paid or all)")
+}
+
+func readAppWidgetProvider(path string) (provider AppWidgetProvider, err error) {
+  data, err := os.ReadFile(path)
+  if err != nil { return }
+  err = xml.Unmarshal(data, &provider)
+  return
+}
+
+// sizeDp is the widget's minimum size, falling back to the classic
+// 70n - 30dp cell formula when only target cells are given.
+func (provider *AppWidgetProvider) sizeDp() (width float64, height float64, err error) {
+  width, err = dimensionOrCells(provider.MinWidth, provider.TargetCellWidth)
+  if err != nil { return }
+  height, err = dimensionOrCells(provider.MinHeight, provider.TargetCellHeight)
+  return
+}
+
+func dimensionOrCells(dimension string, cells string) (float64, error) {
+  if dimension != "" {
+    return strconv.ParseFloat(strings.TrimSuffix(strings.TrimSuffix(dimension, "dip"), "dp"), 64)
+  }
+  if cells != "" {
+    n, err := strconv.Atoi(cells)
+    if err != nil {
+      return 0, err
+    }
+    return float64(70*n - 30), nil
+  }
+  return 0, errors.New("no minWidth/minHeight or target cells, pass --size")
+}
+
+// parseDpSize parses WxH sizes such as 250x110dp or 48x48.
+func parseDpSize(s string) (width float64, height float64, err error) {
+  parts := strings.SplitN(strings.TrimSuffix(strings.ToLower(s), "dp"), "x", 2)
+  if len(parts) != 2 {
+    return 0, 0, fmt.Errorf("invalid size %q, expected WxHdp", s)
+  }
+  width, err = strconv.ParseFloat(strings.TrimSuffix(parts[0], "dp"), 64)
+  if err == nil {
+    height, err = strconv.ParseFloat(parts[1], 64)
+  }
+  if err != nil || width <= 0 || height <= 0 {
+    return 0, 0, fmt.Errorf("invalid size %q, expected WxHdp", s)
+  }
+  return
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+  "encoding/json"
+  "fmt"
+  "io/fs"
+  "log"
+  "os"
+  "path/filepath"
+  "strconv"
+  "strings"
+  "github.com/spf13/cobra"
+)
+
+var importXcassetsCmd = &cobra.Command{
+  Use: "xcassets <asset catalog or imageset>...",
+  Short: "Import the imagesets of an iOS asset catalog.",
+  Long: `Import the imagesets of an iOS asset catalog.
+
+Every imageset's largest image is the master, normally the 3x one which
+lands in xxhdpi (2x is xhdpi, 1x mdpi), and every other density is
+generated from it under the imageset's name made into a valid resource
+name. Dark appearance variants are skipped.
+
+  andy import xcassets ios/App/Assets.xcassets
+  andy import xcassets ios/App/Assets.xcassets/send.imageset`,
+  Args: cobra.MinimumNArgs(1),
+  Run: func(cmd *cobra.Command, args []string) {
+    if err := checkOutputOptions(); err != nil { log.Fatal(err) }
+    var images []importedImage
+    for _, arg := range args {
+      found, err := catalogImages(arg)
+      if err != nil { log.Fatal(err) }
+      images = append(images, found...)
+    }
+    if len(images) == 0 {
+      log.Fatal("no imagesets found.")
+    }
+    if err := importImages(images); err != nil { log.Fatal(err) }
+  },
+}
+
+func init() {
+  addImportCommand(importXcassetsCmd)
+}
+
+// catalogImages lists the images of every imageset under dir by the density
+// of their scale.
+func catalogImages(dir string) (images []importedImage, err error) {
+  err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+    if err != nil {
+      return err
+    }
+    if !entry.IsDir() || filepath.Ext(path) != ".imageset" {
+      return nil
+    }
+    data, err := os.ReadFile(filepath.Join(path, "Contents.json"))
+    if err != nil {
+      return err
+    }
+    var contents catalogContents
+    if err := json.Unmarshal(data, &contents); err != nil {
+      return fmt.Errorf("%s: %v", filepath.Join(path, "Contents.json"), err)
+    }
+    name := strings.TrimSuffix(entry.Name(), ".imageset")
+    for _, image := range contents.Images {
+      ext := filepath.Ext(image.Filename)
+      if image.Filename == "" || !isBitmapExt(ext) {
+        continue
+      }
+      if len(image.Appearances) > 0 {
+        printf("  %s %s, appearance variant\n", yellow("skip"), filepath.Join(path, image.Filename))
+        continue
+      }
+      scale := 1.0
+      if image.Scale != "" {
+        if scale, err = strconv.ParseFloat(strings.TrimSuffix(image.Scale, "x"), 64); err != nil {
+          return fmt.Errorf("%s: invalid scale %q", path, image.Scale)
+        }
+      }
+      density, err := scaleDensity(scale)
+      if err != nil {
+        return fmt.Errorf("%s: %v", path, err)
+      }
+      images = append(images, importedImage{Name: name, Density: density, Path: filepath.Join(path, image.Filename), Ext: strings.ToLower(ext)})
+    }
+    return filepath.SkipDir
+  })
+  return
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+  "log"
+  "os"
+  "path/filepath"
+  "strings"
+  "github.com/spf13/cobra"
+)
+
+var importZeplinCmd = &cobra.Command{
+  Use: "zeplin <export folder>",
+  Short: "Import a Zeplin Android asset export.",
+  Long: `Import a Zeplin Android asset export.
+
+Zeplin exports every asset into a folder per density, named like
+drawable-xhdpi or just xhdpi. The highest density of each asset is copied
+into the res folder under a valid resource name, and the densities Zeplin
+didn't provide are generated from it.
+
+  andy import zeplin ~/Downloads/zeplin-assets`,
+  Args: cobra.ExactArgs(1),
+  Run: func(cmd *cobra.Command, args []string) {
+    if err := checkOutputOptions(); err != nil { log.Fatal(err) }
+    images, err := zeplinImages(args[0])
+    if err != nil { log.Fatal(err) }
+    if err := importImages(images); err != nil { log.Fatal(err) }
+  },
+}
+
+func init() {
+  addImportCommand(importZeplinCmd)
+}
+
+// zeplinImages lists the images in the density folders of a Zeplin export.
+func zeplinImages(dir string) (images []importedImage, err error) {
+  entries, err := os.ReadDir(dir)
+  if err != nil {
+    return nil, err
+  }
+  for _, entry := range entries {
+    density, ok := canonicalToDensity(strings.TrimPrefix(entry.Name(), "drawable-"))
+    if !entry.IsDir() || !ok {
+      continue
+    }
+    files, err := os.ReadDir(filepath.Join(dir, entry.Name()))
+    if err != nil {
+      return nil, err
+    }
+    for _, file := range files {
+      ext := filepath.Ext(file.Name())
+      if file.IsDir() || !isBitmapExt(ext) {
+        continue
+      }
+      name := strings.TrimSuffix(file.Name(), ext)
+      images = append(images, importedImage{Name: name, Density: density, Path: filepath.Join(dir, entry.Name(), file.Name()), Ext: strings.ToLower(ext)})
+    }
+  }
+  return
+}